@@ -0,0 +1,143 @@
+package hashmap
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrValueExists is returned by ConcurrentBiMap.Put when the value is already mapped by another key.
+var ErrValueExists = errors.New("value is already mapped by another key")
+
+// ConcurrentBiMap thread-safe bidirectional map, both keys and values are unique.
+// Both directions are updated atomically, writes are serialized while reads of each direction run concurrently.
+type ConcurrentBiMap[k, v any] struct {
+	forward  *ConcurrentHashMap[k, v]
+	backward *ConcurrentHashMap[v, k]
+	mu       *sync.RWMutex
+}
+
+// NewBiMap returns ConcurrentBiMap with default capacity.
+func NewBiMap[k, v Hasher]() ConcurrentBiMap[k, v] {
+	forward := New[k, v]()
+	backward := New[v, k]()
+	return ConcurrentBiMap[k, v]{
+		forward:  &forward,
+		backward: &backward,
+		mu:       &sync.RWMutex{},
+	}
+}
+
+// NewBiMapWithFuncs returns ConcurrentBiMap with the given key and value funcs and default capacity.
+func NewBiMapWithFuncs[k, v any](khf HashFunc[k], kef EqualsFunc[k], vhf HashFunc[v], vef EqualsFunc[v]) (ConcurrentBiMap[k, v], error) {
+	return NewBiMapWithCapAndFuncs[k, v](defaultCapacity, khf, kef, vhf, vef)
+}
+
+// NewBiMapWithCapAndFuncs returns ConcurrentBiMap with the given capacity, and key and value funcs.
+func NewBiMapWithCapAndFuncs[k, v any](capacity int, khf HashFunc[k], kef EqualsFunc[k], vhf HashFunc[v], vef EqualsFunc[v]) (cbm ConcurrentBiMap[k, v], err error) {
+	forward, err := NewWithCapAndFuncs[k, v](capacity, khf, kef)
+	if err != nil {
+		return
+	}
+	backward, err := NewWithCapAndFuncs[v, k](capacity, vhf, vef)
+	if err != nil {
+		return
+	}
+	cbm.forward = &forward
+	cbm.backward = &backward
+	cbm.mu = &sync.RWMutex{}
+	return
+}
+
+// Put maps the given key to the value.
+// If the value is already mapped by another key, it returns ErrValueExists without any change.
+func (bm *ConcurrentBiMap[k, v]) Put(key k, val v) error {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+	if bk, ok := bm.backward.Get(val); ok && !bm.forward.ef(bk, key) {
+		return ErrValueExists
+	}
+	bm.put(key, val)
+	return nil
+}
+
+// ForcePut maps the given key to the value, removing the entry of the other key mapping the value if any.
+func (bm *ConcurrentBiMap[k, v]) ForcePut(key k, val v) {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+	if bk, ok := bm.backward.Get(val); ok {
+		bm.forward.Remove(bk)
+	}
+	bm.put(key, val)
+}
+
+func (bm *ConcurrentBiMap[k, v]) put(key k, val v) {
+	if old, ok := bm.forward.Get(key); ok {
+		bm.backward.Remove(old)
+	}
+	bm.forward.Put(key, val)
+	bm.backward.Put(val, key)
+}
+
+// Get returns value mapped by the given key.
+func (bm *ConcurrentBiMap[k, v]) Get(key k) (v, bool) {
+	bm.mu.RLock()
+	defer bm.mu.RUnlock()
+	return bm.forward.Get(key)
+}
+
+// GetKey returns the key mapping the given value.
+func (bm *ConcurrentBiMap[k, v]) GetKey(val v) (k, bool) {
+	bm.mu.RLock()
+	defer bm.mu.RUnlock()
+	return bm.backward.Get(val)
+}
+
+// Contains returns if there is an entry mapped by the given key.
+func (bm *ConcurrentBiMap[k, v]) Contains(key k) bool {
+	bm.mu.RLock()
+	defer bm.mu.RUnlock()
+	return bm.forward.Contains(key)
+}
+
+// ContainsValue returns if there is an entry with the given value.
+func (bm *ConcurrentBiMap[k, v]) ContainsValue(val v) bool {
+	bm.mu.RLock()
+	defer bm.mu.RUnlock()
+	return bm.backward.Contains(val)
+}
+
+// Remove removes the entry mapped by the given key and returns its value.
+func (bm *ConcurrentBiMap[k, v]) Remove(key k) (v, bool) {
+	bm.mu.Lock()
+	defer bm.mu.Unlock()
+	val, ok := bm.forward.Remove(key)
+	if ok {
+		bm.backward.Remove(val)
+	}
+	return val, ok
+}
+
+// RemoveValue removes the entry with the given value and returns its key.
+func (bm *ConcurrentBiMap[k, v]) RemoveValue(val v) (k, bool) {
+	inv := bm.Inverse()
+	return inv.Remove(val)
+}
+
+// Size returns the count of entries in the map.
+func (bm *ConcurrentBiMap[k, v]) Size() int {
+	return bm.forward.Size()
+}
+
+// Range calls fn for each entry in the map until fn returns false.
+func (bm *ConcurrentBiMap[k, v]) Range(fn func(key k, val v) bool) {
+	bm.forward.Range(fn)
+}
+
+// Inverse returns a view of the map keyed by values, sharing the entries of the map.
+func (bm *ConcurrentBiMap[k, v]) Inverse() ConcurrentBiMap[v, k] {
+	return ConcurrentBiMap[v, k]{
+		forward:  bm.backward,
+		backward: bm.forward,
+		mu:       bm.mu,
+	}
+}
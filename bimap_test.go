@@ -0,0 +1,93 @@
+package hashmap
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func newIntStringBiMap(t *testing.T) ConcurrentBiMap[int, string] {
+	hf := func(key int) uint32 {
+		return uint32(key)
+	}
+	ef := func(k1, k2 int) bool {
+		return k1 == k2
+	}
+	bm, err := NewBiMapWithFuncs[int, string](hf, ef, hashString, equalsString)
+	if err != nil {
+		t.FailNow()
+	}
+	return bm
+}
+
+func TestConcurrentBiMap(t *testing.T) {
+	bm := newIntStringBiMap(t)
+	for i := 0; i < 1_000; i++ {
+		if err := bm.Put(i, strconv.Itoa(i)); err != nil {
+			t.Logf("err: %v", err)
+			t.FailNow()
+		}
+	}
+	if err := bm.Put(1_000, "0"); err != ErrValueExists {
+		t.Logf("err: %v", err)
+		t.FailNow()
+	}
+	if err := bm.Put(0, "0"); err != nil {
+		t.Logf("err: %v", err)
+		t.FailNow()
+	}
+	if err := bm.Put(0, "zero"); err != nil || bm.ContainsValue("0") {
+		t.Logf("err: %v", err)
+		t.FailNow()
+	}
+	bm.ForcePut(1_000, "1")
+	if bm.Contains(1) || bm.Size() != 1_000 {
+		t.Logf("size: %d", bm.Size())
+		t.FailNow()
+	}
+	if k, ok := bm.GetKey("1"); !ok || k != 1_000 {
+		t.Logf("key: %d, ok: %v", k, ok)
+		t.FailNow()
+	}
+	inv := bm.Inverse()
+	if v, ok := inv.Get("zero"); !ok || v != 0 {
+		t.Logf("value: %d, ok: %v", v, ok)
+		t.FailNow()
+	}
+	if v, ok := inv.Remove("2"); !ok || v != 2 || bm.Contains(2) {
+		t.Logf("value: %d, ok: %v", v, ok)
+		t.FailNow()
+	}
+	if k, ok := bm.RemoveValue("3"); !ok || k != 3 || inv.Contains("3") || bm.Size() != 998 {
+		t.Logf("key: %d, ok: %v", k, ok)
+		t.FailNow()
+	}
+}
+
+func TestConcurrentBiMap_ConcurrentlyPut(t *testing.T) {
+	bm := newIntStringBiMap(t)
+	var wg sync.WaitGroup
+	put := func(offset int) {
+		defer wg.Done()
+		for i := 0; i < 10_000; i++ {
+			bm.ForcePut((i+offset)%100, strconv.Itoa(i%150))
+		}
+	}
+	wg.Add(3)
+	go put(0)
+	go put(7)
+	go put(13)
+	wg.Wait()
+	inv := bm.Inverse()
+	if bm.Size() != inv.Size() {
+		t.Logf("size: %d, inverse size: %d", bm.Size(), inv.Size())
+		t.FailNow()
+	}
+	bm.Range(func(key int, val string) bool {
+		if k, ok := bm.GetKey(val); !ok || k != key {
+			t.Logf("key: %d, value: %s", key, val)
+			t.FailNow()
+		}
+		return true
+	})
+}
@@ -0,0 +1,170 @@
+package hashmap
+
+import (
+	stdsort "sort"
+)
+
+// Entry is a key-value pair of the map.
+type Entry[k, v any] struct {
+	Key   k `json:"key"`
+	Value v `json:"value"`
+}
+
+// PutAll saves all the given entries, taking the lock of each involved bucket once.
+// Entries with equal keys are put in the given order.
+func (m *ConcurrentHashMap[k, v]) PutAll(entries []Entry[k, v]) {
+	hashes := make([]uint32, len(entries))
+	for i, e := range entries {
+		hashes[i] = m.hf(e.Key)
+	}
+	indices, groups := m.groupByBucket(hashes)
+	for gi, i := range indices {
+		b := m.table[i]
+		b.Lock()
+		for _, p := range groups[gi] {
+			m.put(hashes[p], entries[p].Key, entries[p].Value)
+		}
+		b.Unlock()
+	}
+}
+
+// RemoveAll removes the entries mapped by the given keys, taking the lock of each involved bucket once.
+// It returns the count of removed entries.
+func (m *ConcurrentHashMap[k, v]) RemoveAll(keys []k) int {
+	hashes := make([]uint32, len(keys))
+	for i, key := range keys {
+		hashes[i] = m.hf(key)
+	}
+	indices, groups := m.groupByBucket(hashes)
+	removed := 0
+	for gi, i := range indices {
+		b := m.table[i]
+		b.Lock()
+		for _, p := range groups[gi] {
+			if m.remove(hashes[p], keys[p]) != nil {
+				removed++
+			}
+		}
+		b.Unlock()
+	}
+	return removed
+}
+
+// GetMany returns the values of the entries mapped by the given keys in the order of the keys,
+// along with whether each entry exists. It takes the read lock of each involved bucket once.
+func (m *ConcurrentHashMap[k, v]) GetMany(keys []k) ([]v, []bool) {
+	hashes := make([]uint32, len(keys))
+	for i, key := range keys {
+		hashes[i] = m.hf(key)
+	}
+	vals := make([]v, len(keys))
+	oks := make([]bool, len(keys))
+	indices, groups := m.groupByBucket(hashes)
+	for gi, i := range indices {
+		b := m.table[i]
+		b.RLock()
+		for _, p := range groups[gi] {
+			if n := b.get(hashes[p], keys[p], m.ef); n != nil {
+				n.touch()
+				vals[p] = n.value
+				oks[p] = true
+			}
+			m.accessed(hashes[p], keys[p])
+		}
+		b.RUnlock()
+	}
+	return vals, oks
+}
+
+// Clear removes all entries of the map.
+func (m *ConcurrentHashMap[k, v]) Clear() {
+	for i, b := range m.table {
+		b.Lock()
+		m.clear(uint32(i))
+		b.Unlock()
+	}
+}
+
+// RemoveIf removes all entries satisfying the given predicate and returns the count of removed entries.
+// Buckets are filtered one at a time under their locks, pred must not call methods of the map.
+func (m *ConcurrentHashMap[k, v]) RemoveIf(pred func(key k, val v) bool) int {
+	removed := 0
+	var matched []*node[k, v]
+	for _, b := range m.table {
+		b.Lock()
+		matched = matched[:0]
+		b.each(func(n *node[k, v]) bool {
+			if pred(n.key, n.value) {
+				matched = append(matched, &node[k, v]{hash: n.hash, key: n.key})
+			}
+			return true
+		})
+		for _, n := range matched {
+			if m.remove(n.hash, n.key) != nil {
+				removed++
+			}
+		}
+		b.Unlock()
+	}
+	return removed
+}
+
+// RetainIf removes all entries not satisfying the given predicate and returns the count of removed entries.
+func (m *ConcurrentHashMap[k, v]) RetainIf(pred func(key k, val v) bool) int {
+	return m.RemoveIf(func(key k, val v) bool {
+		return !pred(key, val)
+	})
+}
+
+// groupByBucket groups the positions of the given hashes by their bucket index.
+// The bucket indices are returned in ascending order along with the positions in each bucket,
+// positions in a bucket keep their order.
+func (m *ConcurrentHashMap[k, v]) groupByBucket(hashes []uint32) ([]uint32, [][]int) {
+	positions := make([]int, len(hashes))
+	if int(m.capacity) <= 4*len(hashes) {
+		// counting sort is cheaper when the table is small compared to the count of keys
+		offsets := make([]int, m.capacity+1)
+		for _, h := range hashes {
+			offsets[h%m.capacity+1]++
+		}
+		for i := 1; i < len(offsets); i++ {
+			offsets[i] += offsets[i-1]
+		}
+		next := make([]int, m.capacity)
+		copy(next, offsets)
+		for p, h := range hashes {
+			i := h % m.capacity
+			positions[next[i]] = p
+			next[i]++
+		}
+		var indices []uint32
+		var groups [][]int
+		for i := uint32(0); i < m.capacity; i++ {
+			if offsets[i] < offsets[i+1] {
+				indices = append(indices, i)
+				groups = append(groups, positions[offsets[i]:offsets[i+1]])
+			}
+		}
+		return indices, groups
+	}
+	order := make([]uint64, len(hashes))
+	for p, h := range hashes {
+		order[p] = uint64(h%m.capacity)<<32 | uint64(p)
+	}
+	stdsort.Slice(order, func(a, b int) bool {
+		return order[a] < order[b]
+	})
+	var indices []uint32
+	var groups [][]int
+	for j, o := range order {
+		i := uint32(o >> 32)
+		positions[j] = int(uint32(o))
+		if len(indices) == 0 || indices[len(indices)-1] != i {
+			indices = append(indices, i)
+			groups = append(groups, positions[j:j+1])
+		} else {
+			groups[len(groups)-1] = positions[j-len(groups[len(groups)-1]) : j+1]
+		}
+	}
+	return indices, groups
+}
@@ -0,0 +1,231 @@
+package hashmap
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestConcurrentHashMap_PutAll(t *testing.T) {
+	m := NewString[int]()
+	entries := make([]Entry[string, int], 0, 10_000)
+	for i := 0; i < 10_000; i++ {
+		entries = append(entries, Entry[string, int]{Key: strconv.Itoa(i), Value: i})
+	}
+	entries = append(entries, Entry[string, int]{Key: "0", Value: -1})
+	m.PutAll(entries)
+	if s := m.Size(); s != 10_000 {
+		t.Logf("size: %d", s)
+		t.FailNow()
+	}
+	if v, _ := m.Get("0"); v != -1 {
+		t.Logf("value: %d", v)
+		t.FailNow()
+	}
+	for i := 1; i < 10_000; i++ {
+		k := strconv.Itoa(i)
+		if v, ok := m.Get(k); !ok || v != i {
+			t.Logf("key: %s, value: %v, ok: %v", k, v, ok)
+			t.FailNow()
+		}
+	}
+	verifyMap(t, &m)
+}
+
+func TestConcurrentHashMap_RemoveAll(t *testing.T) {
+	m := NewString[int]()
+	keys := make([]string, 0, 10_000)
+	for i := 0; i < 10_000; i++ {
+		m.Put(strconv.Itoa(i), i)
+		if i%2 == 0 {
+			keys = append(keys, strconv.Itoa(i), strconv.Itoa(i+10_000))
+		}
+	}
+	if removed := m.RemoveAll(keys); removed != 5_000 {
+		t.Logf("removed: %d", removed)
+		t.FailNow()
+	}
+	if s := m.Size(); s != 5_000 {
+		t.Logf("size: %d", s)
+		t.FailNow()
+	}
+	for i := 0; i < 10_000; i++ {
+		if ok := m.Contains(strconv.Itoa(i)); ok != (i%2 == 1) {
+			t.Logf("key: %d, ok: %v", i, ok)
+			t.FailNow()
+		}
+	}
+	verifyMap(t, &m)
+}
+
+func TestConcurrentHashMap_GetMany(t *testing.T) {
+	m := NewString[int]()
+	for i := 0; i < 10_000; i++ {
+		m.Put(strconv.Itoa(i), i)
+	}
+	keys := make([]string, 0, 1_000)
+	for i := 9_500; i < 10_500; i++ {
+		keys = append(keys, strconv.Itoa(i))
+	}
+	vals, oks := m.GetMany(keys)
+	if len(vals) != len(keys) || len(oks) != len(keys) {
+		t.FailNow()
+	}
+	for i, k := range keys {
+		if n, _ := strconv.Atoi(k); oks[i] != (n < 10_000) || (oks[i] && vals[i] != n) {
+			t.Logf("key: %s, value: %v, ok: %v", k, vals[i], oks[i])
+			t.FailNow()
+		}
+	}
+}
+
+func TestConcurrentHashMap_GetMany_LargeCap(t *testing.T) {
+	m, _ := NewStringWithCap[int](100_000)
+	for i := 0; i < 10_000; i++ {
+		m.Put(strconv.Itoa(i), i)
+	}
+	keys := []string{"7", "10000", "3", "7", "9999"}
+	vals, oks := m.GetMany(keys)
+	expected := []int{7, 0, 3, 7, 9999}
+	for i := range keys {
+		if vals[i] != expected[i] || oks[i] != (keys[i] != "10000") {
+			t.Logf("key: %s, value: %v, ok: %v", keys[i], vals[i], oks[i])
+			t.FailNow()
+		}
+	}
+}
+
+func TestConcurrentHashMap_Clear(t *testing.T) {
+	m := NewString[int]()
+	for i := 0; i < 10_000; i++ {
+		m.Put(strconv.Itoa(i), i)
+	}
+	m.Clear()
+	if s := m.Size(); s != 0 {
+		t.Logf("size: %d", s)
+		t.FailNow()
+	}
+	if m.Contains("0") {
+		t.FailNow()
+	}
+	m.Put("0", 0)
+	if s := m.Size(); s != 1 {
+		t.Logf("size: %d", s)
+		t.FailNow()
+	}
+}
+
+func TestConcurrentHashMap_RemoveIf_RetainIf(t *testing.T) {
+	m := NewString[int]()
+	for i := 0; i < 10_000; i++ {
+		m.Put(strconv.Itoa(i), i)
+	}
+	removed := m.RemoveIf(func(key string, val int) bool {
+		return val%2 == 0
+	})
+	if removed != 5_000 {
+		t.Logf("removed: %d", removed)
+		t.FailNow()
+	}
+	verifyMap(t, &m)
+	removed = m.RetainIf(func(key string, val int) bool {
+		return val < 5_000
+	})
+	if removed != 2_500 {
+		t.Logf("removed: %d", removed)
+		t.FailNow()
+	}
+	if s := m.Size(); s != 2_500 {
+		t.Logf("size: %d", s)
+		t.FailNow()
+	}
+	for i := 0; i < 10_000; i++ {
+		if ok := m.Contains(strconv.Itoa(i)); ok != (i%2 == 1 && i < 5_000) {
+			t.Logf("key: %d, ok: %v", i, ok)
+			t.FailNow()
+		}
+	}
+	verifyMap(t, &m)
+}
+
+func BenchmarkConcurrentHashMap_PutAll(b *testing.B) {
+	m := NewString[int]()
+	entries := make([]Entry[string, int], 100)
+	for i := 0; i < b.N; i++ {
+		for j := range entries {
+			entries[j] = Entry[string, int]{Key: strconv.Itoa(i*100 + j), Value: j}
+		}
+		m.PutAll(entries)
+	}
+}
+
+func BenchmarkConcurrentHashMap_GetMany(b *testing.B) {
+	m := NewString[int]()
+	for i := 0; i < 100_000; i++ {
+		m.Put(strconv.Itoa(i), i)
+	}
+	keys := make([]string, 500)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i * 100)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.GetMany(keys)
+	}
+}
+
+func BenchmarkConcurrentHashMap_GetMany_Loop(b *testing.B) {
+	m := NewString[int]()
+	for i := 0; i < 100_000; i++ {
+		m.Put(strconv.Itoa(i), i)
+	}
+	keys := make([]string, 500)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i * 100)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		vals := make([]int, len(keys))
+		oks := make([]bool, len(keys))
+		for j, key := range keys {
+			vals[j], oks[j] = m.Get(key)
+		}
+	}
+}
+
+func BenchmarkConcurrentHashMap_ParallelGetMany(b *testing.B) {
+	m := NewString[int]()
+	for i := 0; i < 100_000; i++ {
+		m.Put(strconv.Itoa(i), i)
+	}
+	keys := make([]string, 500)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i * 100)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			m.GetMany(keys)
+		}
+	})
+}
+
+func BenchmarkConcurrentHashMap_ParallelGetMany_Loop(b *testing.B) {
+	m := NewString[int]()
+	for i := 0; i < 100_000; i++ {
+		m.Put(strconv.Itoa(i), i)
+	}
+	keys := make([]string, 500)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i * 100)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			vals := make([]int, len(keys))
+			oks := make([]bool, len(keys))
+			for j, key := range keys {
+				vals[j], oks[j] = m.Get(key)
+			}
+		}
+	})
+}
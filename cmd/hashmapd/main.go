@@ -0,0 +1,70 @@
+// Command hashmapd serves a string type key ConcurrentHashMap over the RESP protocol,
+// and optionally over the memcached protocol and an HTTP API for inspection.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/semihbkgr/hashmap"
+	"github.com/semihbkgr/hashmap/server"
+)
+
+func main() {
+	respAddr := flag.String("resp", "127.0.0.1:6380", "address to serve the RESP protocol on")
+	memcachedAddr := flag.String("memcached", "", "address to serve the memcached protocol on, disabled if empty")
+	httpAddr := flag.String("http", "", "address to serve the HTTP API on, disabled if empty")
+	capacity := flag.Int("capacity", 1024, "capacity of the map")
+	hotKeys := flag.Int("hotkeys", 0, "count of hot keys to track over a minute and report in the HTTP stats, disabled if zero")
+	flag.Parse()
+
+	m, err := hashmap.NewStringWithCap[[]byte](*capacity)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *hotKeys > 0 {
+		if err := m.EnableHotKeys(*hotKeys, time.Minute, 16); err != nil {
+			log.Fatal(err)
+		}
+	}
+	rs := server.NewRESPServer(&m)
+	ms := server.NewMemcachedServer(&m)
+
+	if *memcachedAddr != "" {
+		go func() {
+			log.Printf("serving memcached on %s", *memcachedAddr)
+			if err := ms.ListenAndServe(*memcachedAddr); err != nil && err != server.ErrServerClosed {
+				log.Fatal(err)
+			}
+		}()
+	}
+
+	if *httpAddr != "" {
+		h, err := server.NewHTTPHandler[string, []byte](&m, server.StringKeyCodec{}, server.BytesCodec{})
+		if err != nil {
+			log.Fatal(err)
+		}
+		go func() {
+			log.Printf("serving HTTP on %s", *httpAddr)
+			log.Fatal(http.ListenAndServe(*httpAddr, h))
+		}()
+	}
+
+	go func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+		<-sig
+		_ = ms.Close()
+		_ = rs.Close()
+	}()
+
+	log.Printf("serving RESP on %s", *respAddr)
+	if err := rs.ListenAndServe(*respAddr); err != nil && err != server.ErrServerClosed {
+		log.Fatal(err)
+	}
+}
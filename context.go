@@ -0,0 +1,198 @@
+package hashmap
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// ErrWouldBlock is returned by non-blocking operations when the bucket lock is not available.
+var ErrWouldBlock = errors.New("operation would block")
+
+// PutCtx is like Put, but gives up when the context is done while waiting for the bucket lock.
+func (m *ConcurrentHashMap[k, v]) PutCtx(ctx context.Context, key k, val v) error {
+	h := m.hf(key)
+	b := m.table[h%m.capacity]
+	if err := lockCtx(ctx, b.TryLock, b.Lock, b.Unlock); err != nil {
+		return err
+	}
+	m.put(h, key, val)
+	b.Unlock()
+	return nil
+}
+
+// GetCtx is like Get, but gives up when the context is done while waiting for the bucket lock.
+func (m *ConcurrentHashMap[k, v]) GetCtx(ctx context.Context, key k) (v, bool, error) {
+	h := m.hf(key)
+	b := m.table[h%m.capacity]
+	if err := lockCtx(ctx, b.TryRLock, b.RLock, b.RUnlock); err != nil {
+		return *new(v), false, err
+	}
+	n := b.get(h, key, m.ef)
+	n.touch()
+	b.RUnlock()
+	m.accessed(h, key)
+	if n == nil {
+		return *new(v), false, nil
+	}
+	return n.value, true, nil
+}
+
+// RemoveCtx is like Remove, but gives up when the context is done while waiting for the bucket lock.
+func (m *ConcurrentHashMap[k, v]) RemoveCtx(ctx context.Context, key k) (v, bool, error) {
+	h := m.hf(key)
+	b := m.table[h%m.capacity]
+	if err := lockCtx(ctx, b.TryLock, b.Lock, b.Unlock); err != nil {
+		return *new(v), false, err
+	}
+	n := m.remove(h, key)
+	b.Unlock()
+	if n == nil {
+		return *new(v), false, nil
+	}
+	return n.value, true, nil
+}
+
+// PutAllCtx is like PutAll, but gives up when the context is done while waiting for a bucket lock.
+// Entries of the buckets processed before giving up remain saved.
+func (m *ConcurrentHashMap[k, v]) PutAllCtx(ctx context.Context, entries []Entry[k, v]) error {
+	hashes := make([]uint32, len(entries))
+	for i, e := range entries {
+		hashes[i] = m.hf(e.Key)
+	}
+	indices, groups := m.groupByBucket(hashes)
+	for gi, i := range indices {
+		b := m.table[i]
+		if err := lockCtx(ctx, b.TryLock, b.Lock, b.Unlock); err != nil {
+			return err
+		}
+		for _, p := range groups[gi] {
+			m.put(hashes[p], entries[p].Key, entries[p].Value)
+		}
+		b.Unlock()
+	}
+	return nil
+}
+
+// RemoveAllCtx is like RemoveAll, but gives up when the context is done while waiting for a bucket lock.
+// Entries of the buckets processed before giving up remain removed, and they are included in the returned count.
+func (m *ConcurrentHashMap[k, v]) RemoveAllCtx(ctx context.Context, keys []k) (int, error) {
+	hashes := make([]uint32, len(keys))
+	for i, key := range keys {
+		hashes[i] = m.hf(key)
+	}
+	indices, groups := m.groupByBucket(hashes)
+	removed := 0
+	for gi, i := range indices {
+		b := m.table[i]
+		if err := lockCtx(ctx, b.TryLock, b.Lock, b.Unlock); err != nil {
+			return removed, err
+		}
+		for _, p := range groups[gi] {
+			if m.remove(hashes[p], keys[p]) != nil {
+				removed++
+			}
+		}
+		b.Unlock()
+	}
+	return removed, nil
+}
+
+// GetManyCtx is like GetMany, but gives up when the context is done while waiting for a bucket lock.
+func (m *ConcurrentHashMap[k, v]) GetManyCtx(ctx context.Context, keys []k) ([]v, []bool, error) {
+	hashes := make([]uint32, len(keys))
+	for i, key := range keys {
+		hashes[i] = m.hf(key)
+	}
+	vals := make([]v, len(keys))
+	oks := make([]bool, len(keys))
+	indices, groups := m.groupByBucket(hashes)
+	for gi, i := range indices {
+		b := m.table[i]
+		if err := lockCtx(ctx, b.TryRLock, b.RLock, b.RUnlock); err != nil {
+			return nil, nil, err
+		}
+		for _, p := range groups[gi] {
+			if n := b.get(hashes[p], keys[p], m.ef); n != nil {
+				n.touch()
+				vals[p] = n.value
+				oks[p] = true
+			}
+			m.accessed(hashes[p], keys[p])
+		}
+		b.RUnlock()
+	}
+	return vals, oks, nil
+}
+
+// TryPut is like Put, but returns ErrWouldBlock instead of waiting if the bucket lock is not available.
+func (m *ConcurrentHashMap[k, v]) TryPut(key k, val v) error {
+	h := m.hf(key)
+	b := m.table[h%m.capacity]
+	if !b.TryLock() {
+		return ErrWouldBlock
+	}
+	m.put(h, key, val)
+	b.Unlock()
+	return nil
+}
+
+// TryGet is like Get, but returns ErrWouldBlock instead of waiting if the bucket lock is not available.
+func (m *ConcurrentHashMap[k, v]) TryGet(key k) (v, bool, error) {
+	h := m.hf(key)
+	b := m.table[h%m.capacity]
+	if !b.TryRLock() {
+		return *new(v), false, ErrWouldBlock
+	}
+	n := b.get(h, key, m.ef)
+	n.touch()
+	b.RUnlock()
+	m.accessed(h, key)
+	if n == nil {
+		return *new(v), false, nil
+	}
+	return n.value, true, nil
+}
+
+// lockCtx acquires a lock, giving up when the context is done.
+// The lock is waited for by lock in another goroutine, so that a writer queues like Lock does
+// instead of polling and being starved by a steady stream of readers.
+// If the context is done first, the goroutine releases the lock as soon as it acquires it.
+func lockCtx(ctx context.Context, tryLock func() bool, lock, unlock func()) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if tryLock() {
+		return nil
+	}
+	if ctx.Done() == nil {
+		lock()
+		return nil
+	}
+	const (
+		waiting int32 = iota
+		acquired
+		abandoned
+	)
+	var state int32
+	done := make(chan struct{})
+	go func() {
+		lock()
+		if !atomic.CompareAndSwapInt32(&state, waiting, acquired) {
+			unlock()
+			return
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		if atomic.CompareAndSwapInt32(&state, waiting, abandoned) {
+			return ctx.Err()
+		}
+		// the lock is acquired at the same time the context is done
+		<-done
+		return nil
+	}
+}
@@ -0,0 +1,133 @@
+package hashmap
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConcurrentHashMap_Ctx(t *testing.T) {
+	m := NewString[int]()
+	ctx := context.Background()
+	if err := m.PutCtx(ctx, "a", 1); err != nil {
+		t.FailNow()
+	}
+	if v, ok, err := m.GetCtx(ctx, "a"); err != nil || !ok || v != 1 {
+		t.Logf("value: %d, ok: %v, err: %v", v, ok, err)
+		t.FailNow()
+	}
+	entries := make([]Entry[string, int], 100)
+	keys := make([]string, 100)
+	for i := range entries {
+		entries[i] = Entry[string, int]{Key: strconv.Itoa(i), Value: i}
+		keys[i] = strconv.Itoa(i)
+	}
+	if err := m.PutAllCtx(ctx, entries); err != nil {
+		t.FailNow()
+	}
+	if vals, oks, err := m.GetManyCtx(ctx, keys); err != nil || !oks[42] || vals[42] != 42 {
+		t.Logf("err: %v", err)
+		t.FailNow()
+	}
+	if removed, err := m.RemoveAllCtx(ctx, keys); err != nil || removed != 100 {
+		t.Logf("removed: %d, err: %v", removed, err)
+		t.FailNow()
+	}
+	if v, ok, err := m.RemoveCtx(ctx, "a"); err != nil || !ok || v != 1 || m.Size() != 0 {
+		t.Logf("value: %d, ok: %v, err: %v", v, ok, err)
+		t.FailNow()
+	}
+}
+
+func TestConcurrentHashMap_Ctx_Cancel(t *testing.T) {
+	m := NewString[int]()
+	m.Put("a", 1)
+	b := m.table[m.hf("a")%m.capacity]
+	b.Lock()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := m.PutCtx(ctx, "a", 2); err != context.DeadlineExceeded {
+		t.Logf("err: %v", err)
+		t.FailNow()
+	}
+	if _, _, err := m.GetCtx(ctx, "a"); err != context.DeadlineExceeded {
+		t.Logf("err: %v", err)
+		t.FailNow()
+	}
+	if err := m.TryPut("a", 2); err != ErrWouldBlock {
+		t.Logf("err: %v", err)
+		t.FailNow()
+	}
+	if _, _, err := m.TryGet("a"); err != ErrWouldBlock {
+		t.Logf("err: %v", err)
+		t.FailNow()
+	}
+	done := make(chan error)
+	go func() {
+		done <- m.PutCtx(context.Background(), "a", 3)
+	}()
+	time.Sleep(5 * time.Millisecond)
+	b.Unlock()
+	if err := <-done; err != nil {
+		t.FailNow()
+	}
+	// the waiters of the cancelled calls release the lock once they acquire it
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, _, err := m.TryGet("a"); err != ErrWouldBlock || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if v, ok, err := m.TryGet("a"); err != nil || !ok || v != 3 {
+		t.Logf("value: %d, ok: %v, err: %v", v, ok, err)
+		t.FailNow()
+	}
+	if err := m.TryPut("a", 4); err != nil {
+		t.FailNow()
+	}
+}
+
+func TestConcurrentHashMap_Ctx_ReadContention(t *testing.T) {
+	m := NewString[int]()
+	m.Put("a", 1)
+	b := m.table[m.hf("a")%m.capacity]
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	// overlapping readers keep the bucket read locked, so the lock is never free for TryLock
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				b.RLock()
+				time.Sleep(100 * time.Microsecond)
+				b.RUnlock()
+			}
+		}()
+	}
+	defer func() {
+		close(done)
+		wg.Wait()
+	}()
+	time.Sleep(time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for i := 0; i < 10; i++ {
+		if err := m.PutCtx(ctx, "a", i); err != nil {
+			t.Logf("err: %v", err)
+			t.FailNow()
+		}
+		if _, _, err := m.RemoveCtx(ctx, "a"); err != nil {
+			t.Logf("err: %v", err)
+			t.FailNow()
+		}
+	}
+}
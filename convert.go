@@ -0,0 +1,67 @@
+package hashmap
+
+import "sync/atomic"
+
+// Clone returns an independent copy of the map with the same capacity and funcs.
+// Each bucket is copied under its read lock.
+func (m *ConcurrentHashMap[k, v]) Clone() ConcurrentHashMap[k, v] {
+	chm, _ := NewWithCapAndFuncs[k, v](int(m.capacity), m.hf, m.ef)
+	chm.mode.info = atomic.LoadInt32(&m.mode.info)
+	for i, b := range m.table {
+		b.RLock()
+		chm.table[i] = b.clone()
+		b.RUnlock()
+	}
+	return chm
+}
+
+// FromMap returns ConcurrentHashMap containing the entries of the given map.
+// Keys are compared by the == operator, the capacity is the size of the map but not less than the default capacity.
+func FromMap[k comparable, v any](src map[k]v, hf HashFunc[k]) (chm ConcurrentHashMap[k, v], err error) {
+	ef := func(k1, k2 k) bool {
+		return k1 == k2
+	}
+	chm, err = NewWithCapAndFuncs[k, v](fromMapCap(len(src)), hf, ef)
+	if err != nil {
+		return
+	}
+	load(&chm, src)
+	return
+}
+
+// FromStringMap returns string type key ConcurrentHashMap containing the entries of the given map.
+func FromStringMap[v any](src map[string]v) ConcurrentHashMap[string, v] {
+	chm, _ := NewStringWithCap[v](fromMapCap(len(src)))
+	load(&chm, src)
+	return chm
+}
+
+// ToMap returns a built-in map containing the entries of the given map.
+// Each bucket is read under its read lock.
+func ToMap[k comparable, v any](m *ConcurrentHashMap[k, v]) map[k]v {
+	dst := make(map[k]v, m.Size())
+	for _, b := range m.table {
+		b.RLock()
+		b.each(func(n *node[k, v]) bool {
+			dst[n.key] = n.value
+			return true
+		})
+		b.RUnlock()
+	}
+	return dst
+}
+
+// load puts the entries into buckets directly, the map must not be shared yet.
+func load[k comparable, v any](m *ConcurrentHashMap[k, v], src map[k]v) {
+	for key, val := range src {
+		h := m.hf(key)
+		m.table[h%m.capacity].put(h, key, val, m.ef)
+	}
+}
+
+func fromMapCap(size int) int {
+	if size < defaultCapacity {
+		return defaultCapacity
+	}
+	return size
+}
@@ -0,0 +1,81 @@
+package hashmap
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestConcurrentHashMap_Clone(t *testing.T) {
+	m := NewString[int]()
+	for i := 0; i < 1_000; i++ {
+		m.Put(strconv.Itoa(i), i)
+	}
+	c := m.Clone()
+	for i := 0; i < 500; i++ {
+		m.Remove(strconv.Itoa(i))
+	}
+	c.Put("1000", 1000)
+	if s := c.Size(); s != 1_001 {
+		t.Logf("size: %d", s)
+		t.FailNow()
+	}
+	for i := 0; i < 1_001; i++ {
+		k := strconv.Itoa(i)
+		if v, ok := c.Get(k); !ok || v != i {
+			t.Logf("key: %s, value: %v, ok: %v", k, v, ok)
+			t.FailNow()
+		}
+	}
+	if m.Contains("1000") {
+		t.FailNow()
+	}
+	verifyMap(t, &c)
+}
+
+func TestFromMap(t *testing.T) {
+	src := make(map[int]string)
+	for i := 0; i < 1_000; i++ {
+		src[i] = strconv.Itoa(i)
+	}
+	hf := func(key int) uint32 {
+		return uint32(key)
+	}
+	m, err := FromMap(src, hf)
+	if err != nil {
+		t.FailNow()
+	}
+	if s := m.Size(); s != 1_000 {
+		t.Logf("size: %d", s)
+		t.FailNow()
+	}
+	for key, val := range src {
+		if v, ok := m.Get(key); !ok || v != val {
+			t.Logf("key: %d, value: %v, ok: %v", key, v, ok)
+			t.FailNow()
+		}
+	}
+
+	_, err = FromMap[int, string](src, nil)
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func TestFromStringMap_ToMap(t *testing.T) {
+	src := make(map[string]int)
+	for i := 0; i < 1_000; i++ {
+		src[strconv.Itoa(i)] = i
+	}
+	m := FromStringMap(src)
+	dst := ToMap(&m)
+	if len(dst) != len(src) {
+		t.Logf("len: %d", len(dst))
+		t.FailNow()
+	}
+	for key, val := range src {
+		if v, ok := dst[key]; !ok || v != val {
+			t.Logf("key: %s, value: %v, ok: %v", key, v, ok)
+			t.FailNow()
+		}
+	}
+}
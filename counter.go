@@ -0,0 +1,96 @@
+package hashmap
+
+// Number is the constraint of CounterMap value types.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// CounterMap thread-safe map of numeric counters.
+// Counters are modified under the bucket lock of their keys, and removed once they reach zero.
+type CounterMap[k any, n Number] struct {
+	m ConcurrentHashMap[k, n]
+}
+
+// NewCounterMap returns CounterMap with default capacity.
+func NewCounterMap[k Hasher, n Number]() CounterMap[k, n] {
+	return CounterMap[k, n]{m: New[k, n]()}
+}
+
+// NewStringCounterMap returns string type key CounterMap with default capacity.
+func NewStringCounterMap[n Number]() CounterMap[string, n] {
+	return CounterMap[string, n]{m: NewString[n]()}
+}
+
+// NewCounterMapWithCapAndFuncs returns CounterMap with the given capacity and funcs.
+func NewCounterMapWithCapAndFuncs[k any, n Number](capacity int, hf HashFunc[k], ef EqualsFunc[k]) (cm CounterMap[k, n], err error) {
+	cm.m, err = NewWithCapAndFuncs[k, n](capacity, hf, ef)
+	return
+}
+
+// Add adds delta to the counter of the given key and returns the new value.
+func (cm *CounterMap[k, n]) Add(key k, delta n) n {
+	val, _ := cm.m.Compute(key, func(val n, ok bool) (n, bool) {
+		val += delta
+		return val, val != 0
+	})
+	return val
+}
+
+// Increment adds one to the counter of the given key and returns the new value.
+func (cm *CounterMap[k, n]) Increment(key k) n {
+	return cm.Add(key, 1)
+}
+
+// Decrement subtracts one from the counter of the given key and returns the new value.
+// Counters of unsigned types stop at zero instead of wrapping around, so decrementing an absent one leaves it absent.
+func (cm *CounterMap[k, n]) Decrement(key k) n {
+	one := n(1)
+	unsigned := n(0)-one > 0
+	val, _ := cm.m.Compute(key, func(val n, ok bool) (n, bool) {
+		if unsigned && val == 0 {
+			return 0, false
+		}
+		val -= one
+		return val, val != 0
+	})
+	return val
+}
+
+// Get returns the counter of the given key, zero if there is none.
+func (cm *CounterMap[k, n]) Get(key k) n {
+	val, _ := cm.m.Get(key)
+	return val
+}
+
+// GetAndReset removes the counter of the given key and returns its value.
+func (cm *CounterMap[k, n]) GetAndReset(key k) n {
+	val, _ := cm.m.Remove(key)
+	return val
+}
+
+// Sum returns the sum of all counters.
+// Buckets are summed one at a time, so the result is not a point-in-time sum under concurrent updates.
+func (cm *CounterMap[k, n]) Sum() n {
+	var sum n
+	for _, b := range cm.m.table {
+		b.RLock()
+		b.each(func(nd *node[k, n]) bool {
+			sum += nd.value
+			return true
+		})
+		b.RUnlock()
+	}
+	return sum
+}
+
+// Size returns the count of non-zero counters.
+func (cm *CounterMap[k, n]) Size() int {
+	return cm.m.Size()
+}
+
+// Range calls fn for each counter until fn returns false.
+func (cm *CounterMap[k, n]) Range(fn func(key k, val n) bool) {
+	cm.m.Range(fn)
+}
@@ -0,0 +1,136 @@
+package hashmap
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCounterMap(t *testing.T) {
+	cm := NewStringCounterMap[int64]()
+	for i := 0; i < 1_000; i++ {
+		cm.Increment(strconv.Itoa(i % 10))
+	}
+	if cm.Size() != 10 || cm.Sum() != 1_000 {
+		t.Logf("size: %d, sum: %d", cm.Size(), cm.Sum())
+		t.FailNow()
+	}
+	if v := cm.Add("0", 50); v != 150 {
+		t.Logf("value: %d", v)
+		t.FailNow()
+	}
+	if v := cm.Decrement("1"); v != 99 {
+		t.Logf("value: %d", v)
+		t.FailNow()
+	}
+	if v := cm.GetAndReset("2"); v != 100 || cm.Get("2") != 0 || cm.Size() != 9 {
+		t.Logf("value: %d", v)
+		t.FailNow()
+	}
+	if v := cm.Add("3", -100); v != 0 || cm.m.Contains("3") {
+		t.Logf("value: %d", v)
+		t.FailNow()
+	}
+	if cm.Sum() != 150+99+100*6 {
+		t.Logf("sum: %d", cm.Sum())
+		t.FailNow()
+	}
+	if v := cm.Decrement("a"); v != -1 || cm.Get("a") != -1 {
+		t.Logf("value: %d", v)
+		t.FailNow()
+	}
+}
+
+func TestCounterMap_Decrement_Unsigned(t *testing.T) {
+	cm := NewStringCounterMap[uint64]()
+	if v := cm.Decrement("a"); v != 0 || cm.m.Contains("a") {
+		t.Logf("value: %d", v)
+		t.FailNow()
+	}
+	cm.Add("a", 2)
+	if v := cm.Decrement("a"); v != 1 {
+		t.Logf("value: %d", v)
+		t.FailNow()
+	}
+	if v := cm.Decrement("a"); v != 0 || cm.m.Contains("a") {
+		t.Logf("value: %d", v)
+		t.FailNow()
+	}
+	if v := cm.Decrement("a"); v != 0 || cm.Size() != 0 {
+		t.Logf("value: %d", v)
+		t.FailNow()
+	}
+}
+
+func TestCounterMap_Float(t *testing.T) {
+	cm := NewStringCounterMap[float64]()
+	cm.Add("a", 0.5)
+	cm.Add("a", 0.25)
+	if v := cm.Get("a"); v != 0.75 {
+		t.Logf("value: %v", v)
+		t.FailNow()
+	}
+}
+
+func TestCounterMap_ConcurrentlyIncrement(t *testing.T) {
+	cm := NewStringCounterMap[int]()
+	var wg sync.WaitGroup
+	increment := func() {
+		defer wg.Done()
+		for i := 0; i < 10_000; i++ {
+			cm.Increment(strconv.Itoa(i % 100))
+		}
+	}
+	wg.Add(3)
+	go increment()
+	go increment()
+	go increment()
+	wg.Wait()
+	if cm.Sum() != 30_000 {
+		t.Logf("sum: %d", cm.Sum())
+		t.FailNow()
+	}
+	for i := 0; i < 100; i++ {
+		if v := cm.Get(strconv.Itoa(i)); v != 300 {
+			t.Logf("key: %d, value: %d", i, v)
+			t.FailNow()
+		}
+	}
+}
+
+func BenchmarkCounterMap_Increment(b *testing.B) {
+	cm := NewStringCounterMap[int64]()
+	keys := make([]string, 1_000)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			cm.Increment(keys[i%len(keys)])
+			i++
+		}
+	})
+}
+
+func BenchmarkSyncMapAtomic_Increment(b *testing.B) {
+	var sm sync.Map
+	keys := make([]string, 1_000)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			c, ok := sm.Load(keys[i%len(keys)])
+			if !ok {
+				c, _ = sm.LoadOrStore(keys[i%len(keys)], new(int64))
+			}
+			atomic.AddInt64(c.(*int64), 1)
+			i++
+		}
+	})
+}
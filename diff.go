@@ -0,0 +1,83 @@
+package hashmap
+
+// Change is an entry whose value is changed between two maps.
+type Change[k, v any] struct {
+	Key k `json:"key"`
+	Old v `json:"old"`
+	New v `json:"new"`
+}
+
+// Patch is the difference between two maps, the entries added to, removed from and changed in the first map to get the second one.
+// It can be serialized by encoding/json or encoding/gob, so that it can be stored and applied later.
+type Patch[k, v any] struct {
+	Added   []Entry[k, v]  `json:"added,omitempty"`
+	Removed []Entry[k, v]  `json:"removed,omitempty"`
+	Changed []Change[k, v] `json:"changed,omitempty"`
+}
+
+// Len returns the count of changed keys in the patch.
+func (p *Patch[k, v]) Len() int {
+	return len(p.Added) + len(p.Removed) + len(p.Changed)
+}
+
+// Diff returns the patch which turns the map a into the map b, values are compared by vef.
+// Both maps are compared as of a point-in-time snapshot, and must have the same hash and equals funcs.
+func Diff[k, v any](a, b *ConcurrentHashMap[k, v], vef EqualsFunc[v]) Patch[k, v] {
+	return DiffSnapshots(a.Snapshot(), b.Snapshot(), vef)
+}
+
+// DiffSnapshots returns the patch which turns the snapshot a into the snapshot b, values are compared by vef.
+func DiffSnapshots[k, v any](a, b *Snapshot[k, v], vef EqualsFunc[v]) Patch[k, v] {
+	var p Patch[k, v]
+	b.Range(func(key k, val v) bool {
+		old, ok := a.Get(key)
+		if !ok {
+			p.Added = append(p.Added, Entry[k, v]{Key: key, Value: val})
+		} else if !vef(old, val) {
+			p.Changed = append(p.Changed, Change[k, v]{Key: key, Old: old, New: val})
+		}
+		return true
+	})
+	a.Range(func(key k, val v) bool {
+		if !b.Contains(key) {
+			p.Removed = append(p.Removed, Entry[k, v]{Key: key, Value: val})
+		}
+		return true
+	})
+	return p
+}
+
+// Apply applies the patch to the map, each key is changed atomically under its bucket lock.
+// If vef is not nil, the keys whose current state differs from the state the patch was made from
+// are left as they are and returned as conflicts, the keys which are already in their patched state are skipped.
+// If vef is nil, the patch is applied unconditionally.
+func (m *ConcurrentHashMap[k, v]) Apply(p Patch[k, v], vef EqualsFunc[v]) []k {
+	var conflicts []k
+	apply := func(key k, expected v, existed bool, val v, exists bool) {
+		m.Compute(key, func(cur v, ok bool) (v, bool) {
+			if vef == nil {
+				return val, exists
+			}
+			if ok == exists && (!ok || vef(cur, val)) {
+				// already applied
+				return cur, ok
+			}
+			if ok != existed || ok && !vef(cur, expected) {
+				conflicts = append(conflicts, key)
+			} else {
+				cur, ok = val, exists
+			}
+			return cur, ok
+		})
+	}
+	for _, e := range p.Added {
+		apply(e.Key, *new(v), false, e.Value, true)
+	}
+	for _, c := range p.Changed {
+		apply(c.Key, c.Old, true, c.New, true)
+	}
+	for _, e := range p.Removed {
+		apply(e.Key, e.Value, true, *new(v), false)
+	}
+	return conflicts
+}
@@ -0,0 +1,107 @@
+package hashmap
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+)
+
+func equalsInt(a, b int) bool {
+	return a == b
+}
+
+func TestDiff(t *testing.T) {
+	a := NewString[int]()
+	b := NewString[int]()
+	for i := 0; i < 100; i++ {
+		a.Put(strconv.Itoa(i), i)
+		b.Put(strconv.Itoa(i), i)
+	}
+	a.Put("removed", 1)
+	b.Put("added", 2)
+	b.Put("50", -50)
+	p := Diff(&a, &b, equalsInt)
+	if p.Len() != 3 {
+		t.Logf("patch: %+v", p)
+		t.FailNow()
+	}
+	if p.Added[0] != (Entry[string, int]{Key: "added", Value: 2}) ||
+		p.Removed[0] != (Entry[string, int]{Key: "removed", Value: 1}) ||
+		p.Changed[0] != (Change[string, int]{Key: "50", Old: 50, New: -50}) {
+		t.Logf("patch: %+v", p)
+		t.FailNow()
+	}
+	if p := Diff(&a, &a, equalsInt); p.Len() != 0 {
+		t.Logf("patch: %+v", p)
+		t.FailNow()
+	}
+}
+
+func TestConcurrentHashMap_Apply(t *testing.T) {
+	a := NewString[int]()
+	b := NewString[int]()
+	for i := 0; i < 100; i++ {
+		a.Put(strconv.Itoa(i), i)
+	}
+	s := a.Snapshot()
+	for i := 0; i < 10; i++ {
+		a.Remove(strconv.Itoa(i))
+	}
+	a.Put("100", 100)
+	a.Put("50", -50)
+	p := DiffSnapshots(s, a.Snapshot(), equalsInt)
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Logf("err: %v", err)
+		t.FailNow()
+	}
+	var decoded Patch[string, int]
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Logf("err: %v", err)
+		t.FailNow()
+	}
+	s.Range(func(key string, val int) bool {
+		b.Put(key, val)
+		return true
+	})
+	if conflicts := b.Apply(decoded, equalsInt); len(conflicts) != 0 {
+		t.Logf("conflicts: %v", conflicts)
+		t.FailNow()
+	}
+	if p := Diff(&a, &b, equalsInt); p.Len() != 0 {
+		t.Logf("patch: %+v", p)
+		t.FailNow()
+	}
+	if conflicts := b.Apply(decoded, equalsInt); len(conflicts) != 0 {
+		t.Logf("conflicts on reapply: %v", conflicts)
+		t.FailNow()
+	}
+}
+
+func TestConcurrentHashMap_Apply_Conflicts(t *testing.T) {
+	m := NewString[int]()
+	m.Put("changed", 1)
+	m.Put("removed", 1)
+	p := Patch[string, int]{
+		Added:   []Entry[string, int]{{Key: "added", Value: 1}},
+		Removed: []Entry[string, int]{{Key: "removed", Value: 2}},
+		Changed: []Change[string, int]{{Key: "changed", Old: 2, New: 3}},
+	}
+	m.Put("added", 5)
+	if conflicts := m.Apply(p, equalsInt); len(conflicts) != 3 {
+		t.Logf("conflicts: %v", conflicts)
+		t.FailNow()
+	}
+	if v, _ := m.Get("changed"); v != 1 || !m.Contains("removed") {
+		t.FailNow()
+	}
+	if conflicts := m.Apply(p, nil); conflicts != nil {
+		t.FailNow()
+	}
+	if v, _ := m.Get("changed"); v != 3 || m.Contains("removed") {
+		t.FailNow()
+	}
+	if v, _ := m.Get("added"); v != 1 {
+		t.FailNow()
+	}
+}
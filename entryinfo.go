@@ -0,0 +1,128 @@
+package hashmap
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// EntryInfo is the metadata of an entry, recorded once it is enabled by EnableEntryInfo.
+type EntryInfo struct {
+	// Created is the time the entry is saved first.
+	Created time.Time `json:"created"`
+	// Updated is the time the value of the entry is saved last.
+	Updated time.Time `json:"updated"`
+	// LastAccess is the time the entry is read last, zero if it is not read yet.
+	LastAccess time.Time `json:"last_access"`
+	// Hits is the count of reads of the entry.
+	Hits int64 `json:"hits"`
+}
+
+// entryMode holds whether the metadata of the entries are recorded, shared by the copies of the map.
+type entryMode struct {
+	info int32
+}
+
+// entryMeta is the metadata of a node, created and updated are changed under the bucket lock,
+// accessed and hits are changed atomically under the bucket read lock.
+type entryMeta struct {
+	created  int64
+	updated  int64
+	accessed int64
+	hits     int64
+}
+
+// EnableEntryInfo makes the map record the creation, update and access times and the hit count of entries.
+// Entries saved before it is enabled get their metadata on their next update.
+// Recording costs a clock read on each write and read of an entry, while disabled the cost is a nil check.
+func (m *ConcurrentHashMap[k, v]) EnableEntryInfo() {
+	atomic.StoreInt32(&m.mode.info, 1)
+}
+
+// GetEntryInfo returns the metadata of the entry mapped by the given key, without counting it as an access.
+// It returns false if there is no entry or the entry has no metadata.
+func (m *ConcurrentHashMap[k, v]) GetEntryInfo(key k) (EntryInfo, bool) {
+	h := m.hf(key)
+	b := m.table[h%m.capacity]
+	b.RLock()
+	defer b.RUnlock()
+	n := b.get(h, key, m.ef)
+	if n == nil || n.meta == nil {
+		return EntryInfo{}, false
+	}
+	return n.meta.info(), true
+}
+
+// RangeWithInfo calls fn for each entry in the map with its metadata until fn returns false,
+// the metadata is zero for the entries which have none.
+func (m *ConcurrentHashMap[k, v]) RangeWithInfo(fn func(key k, val v, info EntryInfo) bool) {
+	type entry struct {
+		key  k
+		val  v
+		info EntryInfo
+	}
+	var entries []entry
+	for _, b := range m.table {
+		entries = entries[:0]
+		b.RLock()
+		b.each(func(n *node[k, v]) bool {
+			e := entry{key: n.key, val: n.value}
+			if n.meta != nil {
+				e.info = n.meta.info()
+			}
+			entries = append(entries, e)
+			return true
+		})
+		b.RUnlock()
+		for _, e := range entries {
+			if !fn(e.key, e.val, e.info) {
+				return
+			}
+		}
+	}
+}
+
+// saved records a write of the node, the caller must hold the bucket lock.
+func (m *ConcurrentHashMap[k, v]) saved(n *node[k, v]) {
+	if atomic.LoadInt32(&m.mode.info) == 0 {
+		return
+	}
+	now := time.Now().UnixNano()
+	if n.meta == nil {
+		n.meta = &entryMeta{created: now}
+	}
+	n.meta.updated = now
+}
+
+// touch records a read of the node if it has metadata, n may be nil.
+// The caller must hold the bucket read lock.
+func (n *node[k, v]) touch() {
+	if n == nil || n.meta == nil {
+		return
+	}
+	atomic.StoreInt64(&n.meta.accessed, time.Now().UnixNano())
+	atomic.AddInt64(&n.meta.hits, 1)
+}
+
+func (em *entryMeta) info() EntryInfo {
+	info := EntryInfo{
+		Created: time.Unix(0, em.created),
+		Updated: time.Unix(0, em.updated),
+		Hits:    atomic.LoadInt64(&em.hits),
+	}
+	if accessed := atomic.LoadInt64(&em.accessed); accessed != 0 {
+		info.LastAccess = time.Unix(0, accessed)
+	}
+	return info
+}
+
+func (em *entryMeta) clone() *entryMeta {
+	if em == nil {
+		return nil
+	}
+	return &entryMeta{
+		created:  em.created,
+		updated:  em.updated,
+		accessed: atomic.LoadInt64(&em.accessed),
+		hits:     atomic.LoadInt64(&em.hits),
+	}
+}
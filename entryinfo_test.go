@@ -0,0 +1,143 @@
+package hashmap
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConcurrentHashMap_GetEntryInfo(t *testing.T) {
+	m := NewString[int]()
+	m.Put("untracked", 0)
+	m.EnableEntryInfo()
+	if _, ok := m.GetEntryInfo("untracked"); ok {
+		t.FailNow()
+	}
+	before := time.Now()
+	m.Put("a", 1)
+	info, ok := m.GetEntryInfo("a")
+	if !ok || info.Created.Before(before) || info.Updated != info.Created || !info.LastAccess.IsZero() || info.Hits != 0 {
+		t.Logf("info: %+v, ok: %v", info, ok)
+		t.FailNow()
+	}
+	m.Put("a", 2)
+	m.Get("a")
+	m.GetOrDefault("a", 0)
+	m.GetMany([]string{"a", "b"})
+	updated, _ := m.GetEntryInfo("a")
+	if updated.Created != info.Created || updated.Updated.Before(info.Updated) || updated.LastAccess.Before(updated.Updated) || updated.Hits != 3 {
+		t.Logf("info: %+v", updated)
+		t.FailNow()
+	}
+	m.Put("untracked", 1)
+	if _, ok := m.GetEntryInfo("untracked"); !ok {
+		t.FailNow()
+	}
+	clone := m.Clone()
+	clone.Get("a")
+	if info, _ := m.GetEntryInfo("a"); info.Hits != 3 {
+		t.Logf("hits: %d", info.Hits)
+		t.FailNow()
+	}
+	if info, _ := clone.GetEntryInfo("a"); info.Hits != 4 {
+		t.Logf("clone hits: %d", info.Hits)
+		t.FailNow()
+	}
+}
+
+func TestConcurrentHashMap_GetEntryInfo_Tree(t *testing.T) {
+	m, _ := NewStringWithCap[int](1)
+	m.EnableEntryInfo()
+	for i := 0; i < 100; i++ {
+		m.Put(strconv.Itoa(i), i)
+	}
+	for i := 0; i < 100; i++ {
+		for j := 0; j < i; j++ {
+			m.Get(strconv.Itoa(i))
+		}
+	}
+	for i := 0; i < 100; i += 2 {
+		m.Remove(strconv.Itoa(i))
+	}
+	for i := 1; i < 100; i += 2 {
+		if info, ok := m.GetEntryInfo(strconv.Itoa(i)); !ok || info.Hits != int64(i) {
+			t.Logf("key: %d, info: %+v, ok: %v", i, info, ok)
+			t.FailNow()
+		}
+	}
+}
+
+func TestConcurrentHashMap_RangeWithInfo(t *testing.T) {
+	m := NewString[int]()
+	m.Put("untracked", 0)
+	m.EnableEntryInfo()
+	m.Put("a", 1)
+	m.Get("a")
+	count := 0
+	m.RangeWithInfo(func(key string, val int, info EntryInfo) bool {
+		count++
+		if key == "a" && info.Hits != 1 || key == "untracked" && !info.Created.IsZero() {
+			t.Logf("key: %s, info: %+v", key, info)
+			t.FailNow()
+		}
+		return true
+	})
+	if count != 2 {
+		t.FailNow()
+	}
+}
+
+func TestConcurrentHashMap_ConcurrentlyGetEntryInfo(t *testing.T) {
+	m := NewString[int]()
+	m.EnableEntryInfo()
+	m.Put("a", 0)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1_000; j++ {
+				m.Get("a")
+				_ = m.Snapshot()
+			}
+		}()
+	}
+	wg.Wait()
+	if info, _ := m.GetEntryInfo("a"); info.Hits != 4_000 {
+		t.Logf("hits: %d", info.Hits)
+		t.FailNow()
+	}
+}
+
+func BenchmarkConcurrentHashMap_Get_EntryInfo(b *testing.B) {
+	for _, enabled := range []bool{false, true} {
+		b.Run("enabled="+strconv.FormatBool(enabled), func(b *testing.B) {
+			m := NewString[int]()
+			if enabled {
+				m.EnableEntryInfo()
+			}
+			for i := 0; i < 100_000; i++ {
+				m.Put(strconv.Itoa(i), i)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				m.Get(strconv.Itoa(i % 100_000))
+			}
+		})
+	}
+}
+
+func BenchmarkConcurrentHashMap_Put_EntryInfo(b *testing.B) {
+	for _, enabled := range []bool{false, true} {
+		b.Run("enabled="+strconv.FormatBool(enabled), func(b *testing.B) {
+			m := NewString[int]()
+			if enabled {
+				m.EnableEntryInfo()
+			}
+			for i := 0; i < b.N; i++ {
+				m.Put(strconv.Itoa(i%100_000), i)
+			}
+		})
+	}
+}
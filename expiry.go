@@ -0,0 +1,85 @@
+package hashmap
+
+import (
+	"math"
+	"time"
+)
+
+// SetExpiry sets the expiration time of the entry mapped by the given key, a zero time clears it.
+// It returns false if there is no entry mapped by the key.
+// The expiration time belongs to the value it is set for, putting a value for the key clears it,
+// so every writer of the map resets the expiration of the keys it writes.
+// The map does not remove expired entries by itself, Expiry reports them and RemoveExpired removes them.
+func (m *ConcurrentHashMap[k, v]) SetExpiry(key k, at time.Time) bool {
+	h := m.hf(key)
+	i := h % m.capacity
+	b := m.table[i]
+	b.Lock()
+	defer b.Unlock()
+	return m.setExpiry(i, h, key, unixNano(at))
+}
+
+// Expiry returns the expiration time of the entry mapped by the given key.
+// It returns false if there is no entry or the entry has no expiration time.
+func (m *ConcurrentHashMap[k, v]) Expiry(key k) (time.Time, bool) {
+	h := m.hf(key)
+	b := m.table[h%m.capacity]
+	b.RLock()
+	defer b.RUnlock()
+	n := b.get(h, key, m.ef)
+	if n == nil || n.expireAt == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(0, n.expireAt), true
+}
+
+// RemoveExpired removes the entries whose expiration time is not after now and returns the count of removed entries.
+func (m *ConcurrentHashMap[k, v]) RemoveExpired(now time.Time) int {
+	at := unixNano(now)
+	removed := 0
+	var matched []*node[k, v]
+	for _, b := range m.table {
+		b.Lock()
+		matched = matched[:0]
+		b.each(func(n *node[k, v]) bool {
+			if n.expireAt != 0 && n.expireAt <= at {
+				matched = append(matched, &node[k, v]{hash: n.hash, key: n.key})
+			}
+			return true
+		})
+		for _, n := range matched {
+			if m.remove(n.hash, n.key) != nil {
+				removed++
+			}
+		}
+		b.Unlock()
+	}
+	return removed
+}
+
+// setExpiry sets the expiration time of the node mapped by the given key, the caller must hold the bucket lock.
+func (m *ConcurrentHashMap[k, v]) setExpiry(i, h uint32, key k, at int64) bool {
+	b := m.table[i]
+	n := b.get(h, key, m.ef)
+	if n == nil {
+		return false
+	}
+	// the snapshot gets copies of the nodes, so the node itself is changed after preserving the bucket
+	m.snaps.preserve(i, b)
+	n.expireAt = at
+	return true
+}
+
+// unixNano returns the time in unix nanoseconds, zero for the zero time.
+// Times out of the range are clamped, so a time before 1970 is already expired and a far one never is.
+func unixNano(t time.Time) int64 {
+	switch {
+	case t.IsZero():
+		return 0
+	case t.Before(time.Unix(0, 1)):
+		return 1
+	case t.After(time.Unix(0, math.MaxInt64)):
+		return math.MaxInt64
+	}
+	return t.UnixNano()
+}
@@ -0,0 +1,133 @@
+package hashmap
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestConcurrentHashMap_SetExpiry(t *testing.T) {
+	m := NewString[int]()
+	at := time.Now().Add(time.Hour)
+	if m.SetExpiry("a", at) {
+		t.FailNow()
+	}
+	m.Put("a", 1)
+	if _, ok := m.Expiry("a"); ok {
+		t.FailNow()
+	}
+	if !m.SetExpiry("a", at) {
+		t.FailNow()
+	}
+	if e, ok := m.Expiry("a"); !ok || !e.Equal(at) {
+		t.Logf("expiry: %v", e)
+		t.FailNow()
+	}
+	m.Put("a", 2)
+	if _, ok := m.Expiry("a"); ok {
+		t.Log("put does not clear the expiration time")
+		t.FailNow()
+	}
+	m.SetExpiry("a", at)
+	m.Remove("a")
+	m.Put("a", 3)
+	if _, ok := m.Expiry("a"); ok {
+		t.Log("expiration time outlives the entry")
+		t.FailNow()
+	}
+	m.SetExpiry("a", at)
+	m.SetExpiry("a", time.Time{})
+	if _, ok := m.Expiry("a"); ok {
+		t.FailNow()
+	}
+}
+
+func TestConcurrentHashMap_SetExpiry_Clamp(t *testing.T) {
+	m := NewString[int]()
+	m.Put("a", 1)
+	m.SetExpiry("a", time.Unix(-1, 0))
+	if e, ok := m.Expiry("a"); !ok || e.After(time.Now()) {
+		t.Logf("expiry: %v", e)
+		t.FailNow()
+	}
+	m.SetExpiry("a", time.Unix(1<<40, 0))
+	if e, ok := m.Expiry("a"); !ok || !e.After(time.Now()) {
+		t.Logf("expiry: %v", e)
+		t.FailNow()
+	}
+}
+
+func TestConcurrentHashMap_RemoveExpired(t *testing.T) {
+	m, _ := NewStringWithCap[int](2)
+	now := time.Now()
+	for i := 0; i < 100; i++ {
+		m.Put(strconv.Itoa(i), i)
+		switch i % 3 {
+		case 0:
+			m.SetExpiry(strconv.Itoa(i), now.Add(-time.Second))
+		case 1:
+			m.SetExpiry(strconv.Itoa(i), now.Add(time.Hour))
+		}
+	}
+	if n := m.RemoveExpired(now); n != 34 {
+		t.Logf("removed: %d", n)
+		t.FailNow()
+	}
+	for i := 0; i < 100; i++ {
+		if m.Contains(strconv.Itoa(i)) != (i%3 != 0) {
+			t.Logf("key: %d", i)
+			t.FailNow()
+		}
+	}
+}
+
+func TestConcurrentHashMap_SetExpiry_Snapshot(t *testing.T) {
+	m := NewString[int]()
+	m.Put("a", 1)
+	s := m.Snapshot()
+	m.SetExpiry("a", time.Now().Add(-time.Second))
+	m.RemoveExpired(time.Now())
+	if v, ok := s.Get("a"); !ok || v != 1 {
+		t.FailNow()
+	}
+}
+
+func TestTxView_SetExpiry(t *testing.T) {
+	m := NewString[int]()
+	m.Put("a", 1)
+	at := time.Now().Add(time.Hour)
+	err := m.Tx([]string{"a", "b"}, func(tx TxView[string, int]) error {
+		if tx.SetExpiry("b", at) || !tx.SetExpiry("a", at) {
+			t.FailNow()
+		}
+		if e, ok := tx.Expiry("a"); !ok || !e.Equal(at) {
+			t.FailNow()
+		}
+		tx.Put("b", 2)
+		tx.SetExpiry("b", at)
+		tx.Put("b", 3)
+		if _, ok := tx.Expiry("b"); ok {
+			t.FailNow()
+		}
+		return nil
+	})
+	if err != nil {
+		t.FailNow()
+	}
+	if e, ok := m.Expiry("a"); !ok || !e.Equal(at) {
+		t.FailNow()
+	}
+	if _, ok := m.Expiry("b"); ok {
+		t.FailNow()
+	}
+	_ = m.Tx([]string{"a"}, func(tx TxView[string, int]) error {
+		tx.Put("a", 2)
+		if _, ok := tx.Expiry("a"); ok {
+			t.FailNow()
+		}
+		return nil
+	})
+	if _, ok := m.Expiry("a"); ok {
+		t.FailNow()
+	}
+}
@@ -13,11 +13,13 @@ const (
 )
 
 type node[k, v any] struct {
-	hash  uint32
-	key   k
-	value v
-	right *node[k, v]
-	left  *node[k, v]
+	hash     uint32
+	key      k
+	value    v
+	right    *node[k, v]
+	left     *node[k, v]
+	meta     *entryMeta
+	expireAt int64
 }
 
 type bucket[k, v any] struct {
@@ -25,6 +27,7 @@ type bucket[k, v any] struct {
 	node *node[k, v]
 	tree bool
 	size int64
+	snap uint64
 }
 
 // Hasher general interface to provide hash and equals function
@@ -43,6 +46,11 @@ type ConcurrentHashMap[k, v any] struct {
 	table    []*bucket[k, v]
 	hf       HashFunc[k]
 	ef       EqualsFunc[k]
+	snaps    *snapshots[k, v]
+	obs      *observers[k, v]
+	idx      *indexes[k, v]
+	mode     *entryMode
+	hot      *hotKeys[k]
 }
 
 // New returns ConcurrentHashMap with default capacity.
@@ -76,18 +84,20 @@ func NewWithCap[k Hasher, v any](capacity int) (chm ConcurrentHashMap[k, v], err
 
 // NewStringWithCap returns string type key ConcurrentHashMap with given capacity.
 func NewStringWithCap[v any](capacity int) (chm ConcurrentHashMap[string, v], err error) {
-	hf := func(key string) uint32 {
-		h := fnv.New32a()
-		_, _ = h.Write([]byte(key))
-		return h.Sum32()
-	}
-	ef := func(k1, k2 string) bool {
-		return k1 == k2
-	}
-	chm, err = NewWithCapAndFuncs[string, v](capacity, hf, ef)
+	chm, err = NewWithCapAndFuncs[string, v](capacity, hashString, equalsString)
 	return
 }
 
+func hashString(key string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return h.Sum32()
+}
+
+func equalsString(k1, k2 string) bool {
+	return k1 == k2
+}
+
 // NewWithCapAndFuncs returns ConcurrentHashMap with the given capacity and funcs.
 func NewWithCapAndFuncs[k, v any](capacity int, hf HashFunc[k], ef EqualsFunc[k]) (chm ConcurrentHashMap[k, v], err error) {
 	if capacity <= 0 {
@@ -105,6 +115,11 @@ func NewWithCapAndFuncs[k, v any](capacity int, hf HashFunc[k], ef EqualsFunc[k]
 	}
 	chm.hf = hf
 	chm.ef = ef
+	chm.snaps = &snapshots[k, v]{}
+	chm.obs = &observers[k, v]{}
+	chm.idx = &indexes[k, v]{}
+	chm.mode = &entryMode{}
+	chm.hot = &hotKeys[k]{}
 	return
 }
 
@@ -114,7 +129,7 @@ func (m *ConcurrentHashMap[k, v]) Put(key k, val v) {
 	h := m.hf(key)
 	b := m.table[h%m.capacity]
 	b.Lock()
-	b.put(h, key, val, m.ef)
+	m.put(h, key, val)
 	b.Unlock()
 }
 
@@ -125,7 +140,9 @@ func (m *ConcurrentHashMap[k, v]) Get(key k) (v, bool) {
 	b := m.table[h%m.capacity]
 	b.RLock()
 	n := b.get(h, key, m.ef)
+	n.touch()
 	b.RUnlock()
+	m.accessed(h, key)
 	if n == nil {
 		return *new(v), false
 	}
@@ -139,7 +156,9 @@ func (m *ConcurrentHashMap[k, v]) GetOrDefault(key k, defVal v) v {
 	b := m.table[h%m.capacity]
 	b.RLock()
 	n := b.get(h, key, m.ef)
+	n.touch()
 	b.RUnlock()
+	m.accessed(h, key)
 	if n == nil {
 		return defVal
 	}
@@ -162,7 +181,7 @@ func (m *ConcurrentHashMap[k, v]) Remove(key k) (v, bool) {
 	h := m.hf(key)
 	b := m.table[h%m.capacity]
 	b.Lock()
-	n := b.remove(h, key, m.ef)
+	n := m.remove(h, key)
 	b.Unlock()
 	if n == nil {
 		return *new(v), false
@@ -170,6 +189,75 @@ func (m *ConcurrentHashMap[k, v]) Remove(key k) (v, bool) {
 	return n.value, true
 }
 
+// Compute computes a new value for the given key under its bucket lock.
+// fn is called with the current value and whether an entry exists, if fn returns false the entry is removed,
+// otherwise the returned value is saved. It returns the new value and whether the entry exists.
+// fn must not call methods of the map.
+func (m *ConcurrentHashMap[k, v]) Compute(key k, fn func(val v, ok bool) (v, bool)) (v, bool) {
+	h := m.hf(key)
+	b := m.table[h%m.capacity]
+	b.Lock()
+	defer b.Unlock()
+	var val v
+	n := b.get(h, key, m.ef)
+	if n != nil {
+		val = n.value
+	}
+	nv, keep := fn(val, n != nil)
+	if keep {
+		m.put(h, key, nv)
+		return nv, true
+	}
+	if n != nil {
+		m.remove(h, key)
+	}
+	return *new(v), false
+}
+
+// Range calls fn for each entry in the map until fn returns false.
+// Entries of each bucket are copied under its read lock before fn is called, so fn may modify the map.
+func (m *ConcurrentHashMap[k, v]) Range(fn func(key k, val v) bool) {
+	var entries []Entry[k, v]
+	for _, b := range m.table {
+		entries = entries[:0]
+		b.RLock()
+		b.each(func(n *node[k, v]) bool {
+			entries = append(entries, Entry[k, v]{Key: n.key, Value: n.value})
+			return true
+		})
+		b.RUnlock()
+		for _, e := range entries {
+			if !fn(e.Key, e.Value) {
+				return
+			}
+		}
+	}
+}
+
+// Scan returns the entries of the buckets starting from the bucket at the given cursor, until at least count entries
+// are collected or the end of the table is reached, and the cursor to continue from.
+// Scanning starts with cursor zero, and it is complete when the returned cursor is zero.
+// Entries present during the whole scan are returned exactly once, since the table never grows.
+func (m *ConcurrentHashMap[k, v]) Scan(cursor, count int) ([]Entry[k, v], int) {
+	if cursor < 0 || cursor >= int(m.capacity) {
+		return nil, 0
+	}
+	var entries []Entry[k, v]
+	for ; cursor < int(m.capacity) && len(entries) < count; cursor++ {
+		b := m.table[cursor]
+		b.RLock()
+		b.each(func(n *node[k, v]) bool {
+			entries = append(entries, Entry[k, v]{Key: n.key, Value: n.value})
+			return true
+		})
+		b.RUnlock()
+	}
+	if cursor == int(m.capacity) {
+		cursor = 0
+	}
+	return entries, cursor
+}
+
 // Size returns the count of entries in the map
 func (m *ConcurrentHashMap[k, v]) Size() int {
 	var size int64 = 0
@@ -179,6 +267,72 @@ func (m *ConcurrentHashMap[k, v]) Size() int {
 	return int(size)
 }
 
+// put saves the entry into its bucket, the caller must hold the bucket lock.
+func (m *ConcurrentHashMap[k, v]) put(h uint32, key k, val v) {
+	i := h % m.capacity
+	b := m.table[i]
+	m.snaps.preserve(i, b)
+	m.accessed(h, key)
+	obs := m.obs.load()
+	if len(obs) == 0 {
+		m.saved(b.put(h, key, val, m.ef))
+		return
+	}
+	var old v
+	n := b.get(h, key, m.ef)
+	if n != nil {
+		old = n.value
+	}
+	m.saved(b.put(h, key, val, m.ef))
+	for _, o := range obs {
+		o.onPut(h, key, val, old, n != nil)
+	}
+}
+
+// putIfAbsent saves the entry if there is no entry mapped by the given key and returns whether it is saved.
+func (m *ConcurrentHashMap[k, v]) putIfAbsent(key k, val v) bool {
+	h := m.hf(key)
+	b := m.table[h%m.capacity]
+	b.Lock()
+	defer b.Unlock()
+	if b.get(h, key, m.ef) != nil {
+		return false
+	}
+	m.put(h, key, val)
+	return true
+}
+
+// remove removes the entry from its bucket, the caller must hold the bucket lock.
+func (m *ConcurrentHashMap[k, v]) remove(h uint32, key k) *node[k, v] {
+	i := h % m.capacity
+	b := m.table[i]
+	m.snaps.preserve(i, b)
+	rn := b.remove(h, key, m.ef)
+	if rn != nil {
+		for _, o := range m.obs.load() {
+			o.onRemove(h, key, rn.value)
+		}
+	}
+	return rn
+}
+
+// clear removes all entries of the bucket, the caller must hold the bucket lock.
+func (m *ConcurrentHashMap[k, v]) clear(i uint32) {
+	b := m.table[i]
+	m.snaps.preserve(i, b)
+	if obs := m.obs.load(); len(obs) > 0 {
+		b.each(func(n *node[k, v]) bool {
+			for _, o := range obs {
+				o.onRemove(n.hash, n.key, n.value)
+			}
+			return true
+		})
+	}
+	b.node = nil
+	b.tree = false
+	b.size = 0
+}
+
 func (b *bucket[k, v]) get(h uint32, key k, ef EqualsFunc[k]) *node[k, v] {
 	n := b.node
 	for n != nil {
@@ -194,10 +348,12 @@ func (b *bucket[k, v]) get(h uint32, key k, ef EqualsFunc[k]) *node[k, v] {
 	return nil
 }
 
-func (b *bucket[k, v]) put(h uint32, key k, val v, ef EqualsFunc[k]) {
+// put saves the entry into the bucket and returns its node.
+func (b *bucket[k, v]) put(h uint32, key k, val v, ef EqualsFunc[k]) *node[k, v] {
 	if fn := b.get(h, key, ef); fn != nil {
 		fn.value = val
-		return
+		fn.expireAt = 0
+		return fn
 	}
 	nn := &node[k, v]{
 		hash:  h,
@@ -207,7 +363,7 @@ func (b *bucket[k, v]) put(h uint32, key k, val v, ef EqualsFunc[k]) {
 	if b.node == nil {
 		b.node = nn
 		b.size = 1
-		return
+		return nn
 	}
 	if b.tree {
 		if treePut(b.node, nn, ef) {
@@ -223,6 +379,7 @@ func (b *bucket[k, v]) put(h uint32, key k, val v, ef EqualsFunc[k]) {
 			b.tree = true
 		}
 	}
+	return nn
 }
 
 func (b *bucket[k, v]) remove(h uint32, key k, ef EqualsFunc[k]) (rn *node[k, v]) {
@@ -253,6 +410,48 @@ func (b *bucket[k, v]) remove(h uint32, key k, ef EqualsFunc[k]) (rn *node[k, v]
 	return rn
 }
 
+func (b *bucket[k, v]) each(fn func(n *node[k, v]) bool) bool {
+	if b.tree {
+		return treeEach(b.node, fn)
+	}
+	for n := b.node; n != nil; n = n.right {
+		if !fn(n) {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *bucket[k, v]) clone() *bucket[k, v] {
+	return &bucket[k, v]{
+		node: cloneNode(b.node),
+		tree: b.tree,
+		size: b.size,
+	}
+}
+
+func cloneNode[k, v any](n *node[k, v]) *node[k, v] {
+	if n == nil {
+		return nil
+	}
+	return &node[k, v]{
+		hash:     n.hash,
+		key:      n.key,
+		value:    n.value,
+		right:    cloneNode(n.right),
+		left:     cloneNode(n.left),
+		meta:     n.meta.clone(),
+		expireAt: n.expireAt,
+	}
+}
+
+func treeEach[k, v any](n *node[k, v], fn func(n *node[k, v]) bool) bool {
+	if n == nil {
+		return true
+	}
+	return treeEach(n.left, fn) && fn(n) && treeEach(n.right, fn)
+}
+
 func treeify[k, v any](head *node[k, v]) (root *node[k, v]) {
 	nodes := collect(head)
 	sort(nodes)
@@ -360,13 +559,17 @@ func treeRemove[k, v any](r *node[k, v], h uint32, key k, ef EqualsFunc[k]) (*no
 			spn.right = sn.right
 		}
 		rn := &node[k, v]{
-			hash:  r.hash,
-			key:   r.key,
-			value: r.value,
+			hash:     r.hash,
+			key:      r.key,
+			value:    r.value,
+			meta:     r.meta,
+			expireAt: r.expireAt,
 		}
 		r.hash = sn.hash
 		r.key = sn.key
 		r.value = sn.value
+		r.meta = sn.meta
+		r.expireAt = sn.expireAt
 		return r, rn
 	}
 }
@@ -507,6 +507,88 @@ func TestConcurrentHashMap_Put_Remove_Size(t *testing.T) {
 	}
 }
 
+func TestConcurrentHashMap_Compute(t *testing.T) {
+	m := NewString[int]()
+	var wg sync.WaitGroup
+	increment := func() {
+		defer wg.Done()
+		for i := 0; i < 1_000; i++ {
+			m.Compute(strconv.Itoa(i%10), func(val int, ok bool) (int, bool) {
+				return val + 1, true
+			})
+		}
+	}
+	wg.Add(3)
+	go increment()
+	go increment()
+	go increment()
+	wg.Wait()
+	for i := 0; i < 10; i++ {
+		k := strconv.Itoa(i)
+		if v, ok := m.Get(k); !ok || v != 300 {
+			t.Logf("key: %s, value: %v, ok: %v", k, v, ok)
+			t.FailNow()
+		}
+	}
+	if v, ok := m.Compute("0", func(val int, ok bool) (int, bool) {
+		return val, false
+	}); ok || v != 0 || m.Contains("0") {
+		t.Logf("value: %v, ok: %v", v, ok)
+		t.FailNow()
+	}
+}
+
+func TestConcurrentHashMap_Range(t *testing.T) {
+	m := NewString[int]()
+	for i := 0; i < 10_000; i++ {
+		m.Put(strconv.Itoa(i), i)
+	}
+	count := 0
+	m.Range(func(key string, val int) bool {
+		if key != strconv.Itoa(val) {
+			t.Logf("key: %s, value: %v", key, val)
+			t.FailNow()
+		}
+		m.Remove(key)
+		count++
+		return count < 5_000
+	})
+	if count != 5_000 || m.Size() != 5_000 {
+		t.Logf("count: %d, size: %d", count, m.Size())
+		t.FailNow()
+	}
+}
+
+func TestConcurrentHashMap_Scan(t *testing.T) {
+	m := NewString[int]()
+	for i := 0; i < 1_000; i++ {
+		m.Put(strconv.Itoa(i), i)
+	}
+	seen := make(map[string]bool)
+	cursor := 0
+	for {
+		var entries []Entry[string, int]
+		entries, cursor = m.Scan(cursor, 100)
+		for _, e := range entries {
+			if seen[e.Key] {
+				t.Logf("key: %s", e.Key)
+				t.FailNow()
+			}
+			seen[e.Key] = true
+		}
+		if cursor == 0 {
+			break
+		}
+	}
+	if len(seen) != 1_000 {
+		t.Logf("seen: %d", len(seen))
+		t.FailNow()
+	}
+	if entries, cursor := m.Scan(-1, 100); entries != nil || cursor != 0 {
+		t.FailNow()
+	}
+}
+
 func TestTreeify(t *testing.T) {
 	n := &node[string, int]{
 		hash: rand.Uint32(),
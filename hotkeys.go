@@ -0,0 +1,279 @@
+package hashmap
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	stdsort "sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// HotKey is a frequently accessed key, with its estimated access rate.
+type HotKey[k any] struct {
+	Key k `json:"key"`
+	// Rate is the estimated count of accesses per second over the window.
+	Rate float64 `json:"rate"`
+}
+
+// HotBucket is a frequently accessed bucket, with its access rate.
+type HotBucket struct {
+	Index int `json:"index"`
+	// Rate is the count of accesses per second over the window.
+	Rate float64 `json:"rate"`
+}
+
+// hotKeys holds the tracker of the map, nil until hot key tracking is enabled.
+type hotKeys[k any] struct {
+	tracker atomic.Value
+}
+
+// hotTracker tracks the accesses by Get and Put over a sliding window, approximated by the current
+// and the previous windows. Accesses are counted per bucket, and a random sample of them is fed to
+// a Space-Saving summary, weighted by the accesses of the bucket since its previous sample.
+type hotTracker[k any] struct {
+	topK   int
+	sample int
+	window time.Duration
+	ef     EqualsFunc[k]
+	cur    atomic.Value
+	// mu guards the summaries, the windows and the sampling state
+	mu   sync.Mutex
+	prev *hotWindow[k]
+	last []uint64
+	rnd  *rand.Rand
+}
+
+type hotWindow[k any] struct {
+	start  time.Time
+	counts []uint64
+	next   []uint64
+	keys   *spaceSaving[k]
+}
+
+// spaceSaving is the Space-Saving heavy hitters summary, it keeps a fixed number of counters and
+// replaces the smallest counter by a new key, so counts are overestimated by at most the smallest count.
+type spaceSaving[k any] struct {
+	capacity int
+	entries  []ssEntry[k]
+	index    map[uint32][]int
+}
+
+type ssEntry[k any] struct {
+	hash  uint32
+	key   k
+	count uint64
+}
+
+// EnableHotKeys makes the map track the most accessed keys and buckets by Get and Put over the given window.
+// One in sample accesses of each bucket on average is fed to the key summary, 1 feeds all of them.
+// Accesses of buckets are always counted. It resets the tracked accesses if it is already enabled.
+func (m *ConcurrentHashMap[k, v]) EnableHotKeys(topK int, window time.Duration, sample int) error {
+	if topK <= 0 || window <= 0 || sample <= 0 {
+		return errors.New("top k, window and sample must be positive values")
+	}
+	t := &hotTracker[k]{
+		topK:   topK,
+		sample: sample,
+		window: window,
+		ef:     m.ef,
+		last:   make([]uint64, m.capacity),
+		rnd:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	t.cur.Store(t.newWindow(time.Now(), int(m.capacity)))
+	m.hot.tracker.Store(t)
+	return nil
+}
+
+// HotKeys returns the most accessed keys in the window, ordered by rate, nil if tracking is not enabled.
+func (m *ConcurrentHashMap[k, v]) HotKeys() []HotKey[k] {
+	t, _ := m.hot.tracker.Load().(*hotTracker[k])
+	if t == nil {
+		return nil
+	}
+	return t.hotKeys()
+}
+
+// HotBuckets returns the most accessed buckets in the window, ordered by rate, nil if tracking is not enabled.
+func (m *ConcurrentHashMap[k, v]) HotBuckets() []HotBucket {
+	t, _ := m.hot.tracker.Load().(*hotTracker[k])
+	if t == nil {
+		return nil
+	}
+	return t.hotBuckets()
+}
+
+// accessed records an access of the key if tracking is enabled.
+func (m *ConcurrentHashMap[k, v]) accessed(h uint32, key k) {
+	if t, _ := m.hot.tracker.Load().(*hotTracker[k]); t != nil {
+		t.record(h%m.capacity, h, key)
+	}
+}
+
+func (t *hotTracker[k]) newWindow(start time.Time, capacity int) *hotWindow[k] {
+	w := &hotWindow[k]{
+		start:  start,
+		counts: make([]uint64, capacity),
+		next:   make([]uint64, capacity),
+		keys: &spaceSaving[k]{
+			capacity: 4 * t.topK,
+			index:    make(map[uint32][]int),
+		},
+	}
+	for i := range w.next {
+		w.next[i] = t.skip()
+	}
+	return w
+}
+
+// skip returns a random count of accesses to the next sample, sample on average.
+func (t *hotTracker[k]) skip() uint64 {
+	if t.sample == 1 {
+		return 1
+	}
+	return uint64(1 + t.rnd.Intn(2*t.sample-1))
+}
+
+func (t *hotTracker[k]) record(i uint32, h uint32, key k) {
+	w := t.cur.Load().(*hotWindow[k])
+	c := atomic.AddUint64(&w.counts[i], 1)
+	next := atomic.LoadUint64(&w.next[i])
+	// the access which reaches the next sample claims it, the others go on without locking
+	if c < next || !atomic.CompareAndSwapUint64(&w.next[i], next, math.MaxUint64) {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.rotate(time.Now()) != w {
+		return
+	}
+	weight := c - t.last[i]
+	t.last[i] = c
+	atomic.StoreUint64(&w.next[i], c+t.skip())
+	w.keys.add(h, key, weight, t.ef)
+}
+
+// rotate starts a new window if the current window is over and returns the current window,
+// the caller must hold the lock.
+func (t *hotTracker[k]) rotate(now time.Time) *hotWindow[k] {
+	w := t.cur.Load().(*hotWindow[k])
+	elapsed := now.Sub(w.start)
+	if elapsed < t.window {
+		return w
+	}
+	if elapsed < 2*t.window {
+		t.prev = w
+	} else {
+		t.prev = nil
+	}
+	nw := t.newWindow(w.start.Add(elapsed/t.window*t.window), len(w.counts))
+	for i := range t.last {
+		t.last[i] = 0
+	}
+	t.cur.Store(nw)
+	return nw
+}
+
+// weights returns the weights of the current and the previous windows, so that the counts of the sliding window
+// are the counts of the current window plus the counts of the previous window times the part of it still in the sliding window.
+func (t *hotTracker[k]) weights() (*hotWindow[k], *hotWindow[k], float64) {
+	now := time.Now()
+	w := t.rotate(now)
+	return w, t.prev, 1 - float64(now.Sub(w.start))/float64(t.window)
+}
+
+func (t *hotTracker[k]) hotKeys() []HotKey[k] {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	w, prev, pw := t.weights()
+	var keys []HotKey[k]
+	for _, e := range w.keys.entries {
+		count := float64(e.count)
+		if prev != nil {
+			if pe := prev.keys.get(e.hash, e.key, t.ef); pe != nil {
+				count += float64(pe.count) * pw
+			}
+		}
+		keys = append(keys, HotKey[k]{Key: e.key, Rate: count / t.window.Seconds()})
+	}
+	if prev != nil {
+		for _, pe := range prev.keys.entries {
+			if w.keys.get(pe.hash, pe.key, t.ef) == nil {
+				keys = append(keys, HotKey[k]{Key: pe.key, Rate: float64(pe.count) * pw / t.window.Seconds()})
+			}
+		}
+	}
+	stdsort.SliceStable(keys, func(i, j int) bool {
+		return keys[i].Rate > keys[j].Rate
+	})
+	if len(keys) > t.topK {
+		keys = keys[:t.topK]
+	}
+	return keys
+}
+
+func (t *hotTracker[k]) hotBuckets() []HotBucket {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	w, prev, pw := t.weights()
+	var buckets []HotBucket
+	for i := range w.counts {
+		count := float64(atomic.LoadUint64(&w.counts[i]))
+		if prev != nil {
+			count += float64(atomic.LoadUint64(&prev.counts[i])) * pw
+		}
+		if count > 0 {
+			buckets = append(buckets, HotBucket{Index: i, Rate: count / t.window.Seconds()})
+		}
+	}
+	stdsort.SliceStable(buckets, func(i, j int) bool {
+		return buckets[i].Rate > buckets[j].Rate
+	})
+	if len(buckets) > t.topK {
+		buckets = buckets[:t.topK]
+	}
+	return buckets
+}
+
+func (ss *spaceSaving[k]) get(h uint32, key k, ef EqualsFunc[k]) *ssEntry[k] {
+	for _, i := range ss.index[h] {
+		if ef(ss.entries[i].key, key) {
+			return &ss.entries[i]
+		}
+	}
+	return nil
+}
+
+func (ss *spaceSaving[k]) add(h uint32, key k, weight uint64, ef EqualsFunc[k]) {
+	if e := ss.get(h, key, ef); e != nil {
+		e.count += weight
+		return
+	}
+	if len(ss.entries) < ss.capacity {
+		ss.index[h] = append(ss.index[h], len(ss.entries))
+		ss.entries = append(ss.entries, ssEntry[k]{hash: h, key: key, count: weight})
+		return
+	}
+	mi := 0
+	for i, e := range ss.entries {
+		if e.count < ss.entries[mi].count {
+			mi = i
+		}
+	}
+	me := ss.entries[mi]
+	positions := ss.index[me.hash]
+	for j, i := range positions {
+		if i == mi {
+			positions = append(positions[:j], positions[j+1:]...)
+			break
+		}
+	}
+	if len(positions) == 0 {
+		delete(ss.index, me.hash)
+	} else {
+		ss.index[me.hash] = positions
+	}
+	ss.index[h] = append(ss.index[h], mi)
+	ss.entries[mi] = ssEntry[k]{hash: h, key: key, count: me.count + weight}
+}
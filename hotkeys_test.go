@@ -0,0 +1,134 @@
+package hashmap
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConcurrentHashMap_HotKeys(t *testing.T) {
+	m := NewString[int]()
+	if m.HotKeys() != nil || m.HotBuckets() != nil {
+		t.FailNow()
+	}
+	if err := m.EnableHotKeys(3, time.Minute, 1); err != nil {
+		t.Logf("err: %v", err)
+		t.FailNow()
+	}
+	for i := 0; i < 1_000; i++ {
+		m.Put(strconv.Itoa(i), i)
+	}
+	for i := 0; i < 10_000; i++ {
+		m.Get("hot")
+		if i%2 == 0 {
+			m.Put("warm", i)
+		}
+		m.Get(strconv.Itoa(i % 1_000))
+	}
+	keys := m.HotKeys()
+	if len(keys) != 3 || keys[0].Key != "hot" || keys[1].Key != "warm" {
+		t.Logf("keys: %+v", keys)
+		t.FailNow()
+	}
+	if keys[0].Rate < 10_000/time.Minute.Seconds() || keys[1].Rate >= keys[0].Rate {
+		t.Logf("keys: %+v", keys)
+		t.FailNow()
+	}
+	buckets := m.HotBuckets()
+	hot := int(hashString("hot") % m.capacity)
+	if len(buckets) != 3 || buckets[0].Index != hot {
+		t.Logf("buckets: %+v, hot: %d", buckets, hot)
+		t.FailNow()
+	}
+	if stats := m.Stats(); len(stats.HotBuckets) != 3 {
+		t.Logf("stats: %+v", stats)
+		t.FailNow()
+	}
+}
+
+func TestConcurrentHashMap_HotKeys_Sampled(t *testing.T) {
+	m, _ := NewStringWithCap[int](64)
+	_ = m.EnableHotKeys(2, time.Minute, 16)
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 20_000; i++ {
+				m.Get("hot")
+				m.Get(strconv.Itoa(w*100_000 + i))
+				if i%3 == 0 {
+					m.Put("warm", i)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	keys := m.HotKeys()
+	if len(keys) != 2 || keys[0].Key != "hot" || keys[1].Key != "warm" {
+		t.Logf("keys: %+v", keys)
+		t.FailNow()
+	}
+}
+
+func TestConcurrentHashMap_HotKeys_Window(t *testing.T) {
+	m := NewString[int]()
+	_ = m.EnableHotKeys(1, time.Minute, 1)
+	for i := 0; i < 100; i++ {
+		m.Get("old")
+	}
+	tr := m.hot.tracker.Load().(*hotTracker[string])
+	tr.mu.Lock()
+	w := tr.cur.Load().(*hotWindow[string])
+	w.start = w.start.Add(-90 * time.Second)
+	tr.mu.Unlock()
+	for i := 0; i < 60; i++ {
+		m.Get("new")
+	}
+	keys := m.HotKeys()
+	if len(keys) != 1 || keys[0].Key != "new" {
+		t.Logf("keys: %+v", keys)
+		t.FailNow()
+	}
+	tr.mu.Lock()
+	w = tr.cur.Load().(*hotWindow[string])
+	w.start = w.start.Add(-2 * time.Minute)
+	tr.mu.Unlock()
+	if keys := m.HotKeys(); len(keys) != 0 {
+		t.Logf("keys: %+v", keys)
+		t.FailNow()
+	}
+}
+
+func TestSpaceSaving(t *testing.T) {
+	ss := &spaceSaving[string]{capacity: 4, index: map[uint32][]int{}}
+	for i := 0; i < 1_000; i++ {
+		ss.add(hashString("a"), "a", 1, equalsString)
+		key := strconv.Itoa(i)
+		ss.add(hashString(key), key, 1, equalsString)
+	}
+	e := ss.get(hashString("a"), "a", equalsString)
+	if e == nil || e.count < 1_000 || len(ss.entries) != 4 || len(ss.index) > 4 {
+		t.Logf("entry: %+v, entries: %d", e, len(ss.entries))
+		t.FailNow()
+	}
+}
+
+func BenchmarkConcurrentHashMap_Get_HotKeys(b *testing.B) {
+	for _, enabled := range []bool{false, true} {
+		b.Run("enabled="+strconv.FormatBool(enabled), func(b *testing.B) {
+			m := NewString[int]()
+			if enabled {
+				_ = m.EnableHotKeys(10, time.Minute, 16)
+			}
+			for i := 0; i < 100_000; i++ {
+				m.Put(strconv.Itoa(i), i)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				m.Get(strconv.Itoa(i % 100_000))
+			}
+		})
+	}
+}
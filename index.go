@@ -0,0 +1,139 @@
+package hashmap
+
+import (
+	"errors"
+	"sync"
+)
+
+var (
+	// ErrIndexExists is returned by AddIndex when there is already an index with the given name.
+	ErrIndexExists = errors.New("index already exists")
+	// ErrIndexNotFound is returned by index operations when there is no index with the given name.
+	ErrIndexNotFound = errors.New("index not found")
+)
+
+// IndexKey is a key of a secondary index, its dynamic type must be comparable.
+type IndexKey interface{}
+
+// IndexFunc returns the index keys of the given value.
+type IndexFunc[v any] func(val v) []IndexKey
+
+type indexes[k, v any] struct {
+	sync.RWMutex
+	named map[string]*valueIndex[k, v]
+}
+
+type valueIndex[k, v any] struct {
+	sync.RWMutex
+	m       *ConcurrentHashMap[k, v]
+	fn      IndexFunc[v]
+	entries map[IndexKey]*ConcurrentHashSet[k]
+}
+
+// AddIndex registers a secondary index over the values of the map with the given name.
+// The index is built from the current entries, and maintained atomically with the changes of the map
+// under their bucket locks.
+func (m *ConcurrentHashMap[k, v]) AddIndex(name string, fn IndexFunc[v]) error {
+	if fn == nil {
+		return errors.New("index func cannot be nil")
+	}
+	m.idx.Lock()
+	defer m.idx.Unlock()
+	if _, ok := m.idx.named[name]; ok {
+		return ErrIndexExists
+	}
+	vi := &valueIndex[k, v]{
+		m:       m,
+		fn:      fn,
+		entries: make(map[IndexKey]*ConcurrentHashSet[k]),
+	}
+	m.obs.add(vi)
+	m.visit(0, int(m.capacity), func(n *node[k, v]) bool {
+		vi.Lock()
+		vi.add(n.key, fn(n.value))
+		vi.Unlock()
+		return true
+	})
+	if m.idx.named == nil {
+		m.idx.named = make(map[string]*valueIndex[k, v])
+	}
+	m.idx.named[name] = vi
+	return nil
+}
+
+// RemoveIndex unregisters the index with the given name.
+func (m *ConcurrentHashMap[k, v]) RemoveIndex(name string) error {
+	m.idx.Lock()
+	defer m.idx.Unlock()
+	vi, ok := m.idx.named[name]
+	if !ok {
+		return ErrIndexNotFound
+	}
+	m.obs.delete(vi)
+	delete(m.idx.named, name)
+	return nil
+}
+
+// LookupIndex returns the keys of the entries whose values have the given index key in the index with the given name.
+func (m *ConcurrentHashMap[k, v]) LookupIndex(name string, ik IndexKey) ([]k, error) {
+	m.idx.RLock()
+	vi, ok := m.idx.named[name]
+	m.idx.RUnlock()
+	if !ok {
+		return nil, ErrIndexNotFound
+	}
+	vi.RLock()
+	defer vi.RUnlock()
+	s := vi.entries[ik]
+	if s == nil {
+		return nil, nil
+	}
+	var keys []k
+	s.Range(func(key k) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys, nil
+}
+
+func (vi *valueIndex[k, v]) onPut(h uint32, key k, val v, old v, replaced bool) {
+	vi.Lock()
+	defer vi.Unlock()
+	if replaced {
+		vi.remove(key, vi.fn(old))
+	}
+	vi.add(key, vi.fn(val))
+}
+
+func (vi *valueIndex[k, v]) onRemove(h uint32, key k, val v) {
+	vi.Lock()
+	defer vi.Unlock()
+	vi.remove(key, vi.fn(val))
+}
+
+// add adds the key to the given index keys, the caller must hold the index lock.
+func (vi *valueIndex[k, v]) add(key k, iks []IndexKey) {
+	for _, ik := range iks {
+		s := vi.entries[ik]
+		if s == nil {
+			ns, _ := NewSetWithCapAndFuncs[k](defaultCapacity, vi.m.hf, vi.m.ef)
+			s = &ns
+			vi.entries[ik] = s
+		}
+		s.Add(key)
+	}
+}
+
+// remove removes the key from the given index keys, the caller must hold the index lock.
+func (vi *valueIndex[k, v]) remove(key k, iks []IndexKey) {
+	for _, ik := range iks {
+		s := vi.entries[ik]
+		if s == nil {
+			continue
+		}
+		s.Remove(key)
+		if s.Size() == 0 {
+			delete(vi.entries, ik)
+		}
+	}
+}
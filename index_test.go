@@ -0,0 +1,119 @@
+package hashmap
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+type session struct {
+	userID int
+	roles  []string
+}
+
+func TestConcurrentHashMap_AddIndex_LookupIndex(t *testing.T) {
+	m := NewString[session]()
+	for i := 0; i < 100; i++ {
+		m.Put("s"+strconv.Itoa(i), session{userID: i % 10})
+	}
+	err := m.AddIndex("user", func(val session) []IndexKey {
+		return []IndexKey{val.userID}
+	})
+	if err != nil {
+		t.FailNow()
+	}
+	err = m.AddIndex("role", func(val session) []IndexKey {
+		iks := make([]IndexKey, len(val.roles))
+		for i, r := range val.roles {
+			iks[i] = r
+		}
+		return iks
+	})
+	if err != nil {
+		t.FailNow()
+	}
+	if err = m.AddIndex("user", func(val session) []IndexKey { return nil }); err != ErrIndexExists {
+		t.Logf("err: %v", err)
+		t.FailNow()
+	}
+
+	keys, err := m.LookupIndex("user", 3)
+	if err != nil || len(keys) != 10 {
+		t.Logf("keys: %v, err: %v", keys, err)
+		t.FailNow()
+	}
+	m.Put("s3", session{userID: 4, roles: []string{"admin", "dev"}})
+	m.Remove("s13")
+	m.Compute("s23", func(val session, ok bool) (session, bool) {
+		val.roles = []string{"dev"}
+		return val, true
+	})
+	if keys, _ = m.LookupIndex("user", 3); len(keys) != 8 {
+		t.Logf("keys: %v", keys)
+		t.FailNow()
+	}
+	if keys, _ = m.LookupIndex("user", 4); len(keys) != 11 {
+		t.Logf("keys: %v", keys)
+		t.FailNow()
+	}
+	if keys, _ = m.LookupIndex("role", "dev"); len(keys) != 2 {
+		t.Logf("keys: %v", keys)
+		t.FailNow()
+	}
+	if keys, _ = m.LookupIndex("role", "admin"); len(keys) != 1 || keys[0] != "s3" {
+		t.Logf("keys: %v", keys)
+		t.FailNow()
+	}
+	if keys, _ = m.LookupIndex("user", 42); keys != nil {
+		t.Logf("keys: %v", keys)
+		t.FailNow()
+	}
+	if err = m.RemoveIndex("role"); err != nil {
+		t.FailNow()
+	}
+	if _, err = m.LookupIndex("role", "dev"); err != ErrIndexNotFound {
+		t.Logf("err: %v", err)
+		t.FailNow()
+	}
+	if err = m.RemoveIndex("role"); err != ErrIndexNotFound {
+		t.Logf("err: %v", err)
+		t.FailNow()
+	}
+}
+
+func TestConcurrentHashMap_ConcurrentlyPut_LookupIndex(t *testing.T) {
+	m := NewString[int]()
+	err := m.AddIndex("mod", func(val int) []IndexKey {
+		return []IndexKey{val % 10}
+	})
+	if err != nil {
+		t.FailNow()
+	}
+	var wg sync.WaitGroup
+	put := func(from, to int) {
+		defer wg.Done()
+		for i := from; i < to; i++ {
+			m.Put(strconv.Itoa(i%1_000), i)
+		}
+	}
+	wg.Add(3)
+	go put(0, 10_000)
+	go put(5_000, 15_000)
+	go put(10_000, 20_000)
+	wg.Wait()
+	total := 0
+	for i := 0; i < 10; i++ {
+		keys, _ := m.LookupIndex("mod", i)
+		for _, key := range keys {
+			if v, _ := m.Get(key); v%10 != i {
+				t.Logf("key: %s, value: %d", key, v)
+				t.FailNow()
+			}
+		}
+		total += len(keys)
+	}
+	if total != 1_000 {
+		t.Logf("total: %d", total)
+		t.FailNow()
+	}
+}
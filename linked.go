@@ -0,0 +1,254 @@
+package hashmap
+
+import (
+	"sync"
+)
+
+// LinkedConcurrentHashMap thread-safe map which keeps its entries in a doubly linked list,
+// so that they are visited in insertion order, or in access order if it is enabled.
+type LinkedConcurrentHashMap[k, v any] struct {
+	m    ConcurrentHashMap[k, *linkedEntry[k, v]]
+	list *linkedList[k, v]
+}
+
+type linkedEntry[k, v any] struct {
+	key    k
+	value  v
+	linked bool
+	before *linkedEntry[k, v]
+	after  *linkedEntry[k, v]
+}
+
+type linkedList[k, v any] struct {
+	sync.Mutex
+	head         *linkedEntry[k, v]
+	tail         *linkedEntry[k, v]
+	accessOrder  bool
+	removeEldest func(key k, val v, size int) bool
+}
+
+// NewLinked returns LinkedConcurrentHashMap with default capacity.
+// If accessOrder is true, entries are ordered from the least recently accessed to the most recently accessed.
+func NewLinked[k Hasher, v any](accessOrder bool) LinkedConcurrentHashMap[k, v] {
+	return LinkedConcurrentHashMap[k, v]{
+		m:    New[k, *linkedEntry[k, v]](),
+		list: &linkedList[k, v]{accessOrder: accessOrder},
+	}
+}
+
+// NewLinkedString returns string type key LinkedConcurrentHashMap with default capacity.
+func NewLinkedString[v any](accessOrder bool) LinkedConcurrentHashMap[string, v] {
+	return LinkedConcurrentHashMap[string, v]{
+		m:    NewString[*linkedEntry[string, v]](),
+		list: &linkedList[string, v]{accessOrder: accessOrder},
+	}
+}
+
+// NewLinkedWithCapAndFuncs returns LinkedConcurrentHashMap with the given capacity and funcs.
+func NewLinkedWithCapAndFuncs[k, v any](capacity int, hf HashFunc[k], ef EqualsFunc[k], accessOrder bool) (lm LinkedConcurrentHashMap[k, v], err error) {
+	lm.m, err = NewWithCapAndFuncs[k, *linkedEntry[k, v]](capacity, hf, ef)
+	lm.list = &linkedList[k, v]{accessOrder: accessOrder}
+	return
+}
+
+// SetRemoveEldestFunc sets the hook which is called with the eldest entry and the size of the map
+// after a new entry is put. If it returns true, the eldest entry is removed.
+func (lm *LinkedConcurrentHashMap[k, v]) SetRemoveEldestFunc(fn func(key k, val v, size int) bool) {
+	lm.list.Lock()
+	lm.list.removeEldest = fn
+	lm.list.Unlock()
+}
+
+// Put maps the given key to the value, and saves the entry.
+// Updating the value of an existing entry keeps its position in insertion order.
+func (lm *LinkedConcurrentHashMap[k, v]) Put(key k, val v) {
+	inserted := false
+	lm.m.Compute(key, func(e *linkedEntry[k, v], ok bool) (*linkedEntry[k, v], bool) {
+		ne := &linkedEntry[k, v]{key: key, value: val}
+		lm.list.Lock()
+		if ok {
+			lm.list.replace(e, ne)
+		} else {
+			lm.list.append(ne)
+			inserted = true
+		}
+		lm.list.Unlock()
+		return ne, true
+	})
+	if !inserted {
+		return
+	}
+	lm.list.Lock()
+	fn := lm.list.removeEldest
+	eldest := lm.list.head
+	lm.list.Unlock()
+	if fn != nil && eldest != nil && fn(eldest.key, eldest.value, lm.Size()) {
+		lm.removeEntry(eldest)
+	}
+}
+
+// Get returns value of the entry mapped by given key.
+// In access order, the entry becomes the most recently accessed one.
+func (lm *LinkedConcurrentHashMap[k, v]) Get(key k) (v, bool) {
+	e, ok := lm.m.Get(key)
+	if !ok {
+		return *new(v), false
+	}
+	if lm.list.accessOrder {
+		lm.list.Lock()
+		if e.linked {
+			lm.list.unlink(e)
+			lm.list.append(e)
+		}
+		lm.list.Unlock()
+	}
+	return e.value, true
+}
+
+// Contains returns if there is an entry mapped by the given key, without affecting access order.
+func (lm *LinkedConcurrentHashMap[k, v]) Contains(key k) bool {
+	return lm.m.Contains(key)
+}
+
+// Remove removes the entry mapped by the given key and returns value of removed entry and true.
+func (lm *LinkedConcurrentHashMap[k, v]) Remove(key k) (v, bool) {
+	var removed *linkedEntry[k, v]
+	lm.m.Compute(key, func(e *linkedEntry[k, v], ok bool) (*linkedEntry[k, v], bool) {
+		if ok {
+			lm.list.Lock()
+			lm.list.unlink(e)
+			lm.list.Unlock()
+			removed = e
+		}
+		return nil, false
+	})
+	if removed == nil {
+		return *new(v), false
+	}
+	return removed.value, true
+}
+
+// Size returns the count of entries in the map.
+func (lm *LinkedConcurrentHashMap[k, v]) Size() int {
+	return lm.m.Size()
+}
+
+// First returns the eldest entry.
+func (lm *LinkedConcurrentHashMap[k, v]) First() (k, v, bool) {
+	lm.list.Lock()
+	defer lm.list.Unlock()
+	if lm.list.head == nil {
+		return *new(k), *new(v), false
+	}
+	return lm.list.head.key, lm.list.head.value, true
+}
+
+// Last returns the youngest entry.
+func (lm *LinkedConcurrentHashMap[k, v]) Last() (k, v, bool) {
+	lm.list.Lock()
+	defer lm.list.Unlock()
+	if lm.list.tail == nil {
+		return *new(k), *new(v), false
+	}
+	return lm.list.tail.key, lm.list.tail.value, true
+}
+
+// RemoveEldest removes the eldest entry and returns it.
+func (lm *LinkedConcurrentHashMap[k, v]) RemoveEldest() (k, v, bool) {
+	for {
+		lm.list.Lock()
+		eldest := lm.list.head
+		lm.list.Unlock()
+		if eldest == nil {
+			return *new(k), *new(v), false
+		}
+		if lm.removeEntry(eldest) {
+			return eldest.key, eldest.value, true
+		}
+	}
+}
+
+// Range calls fn for each entry in order until fn returns false.
+// Entries are copied under the list lock before fn is called, so fn may modify the map.
+func (lm *LinkedConcurrentHashMap[k, v]) Range(fn func(key k, val v) bool) {
+	lm.list.Lock()
+	entries := make([]*linkedEntry[k, v], 0, lm.Size())
+	for e := lm.list.head; e != nil; e = e.after {
+		entries = append(entries, e)
+	}
+	lm.list.Unlock()
+	for _, e := range entries {
+		if !fn(e.key, e.value) {
+			return
+		}
+	}
+}
+
+// removeEntry removes the given entry if it is still mapped by its key.
+func (lm *LinkedConcurrentHashMap[k, v]) removeEntry(re *linkedEntry[k, v]) bool {
+	removed := false
+	lm.m.Compute(re.key, func(e *linkedEntry[k, v], ok bool) (*linkedEntry[k, v], bool) {
+		if !ok || e != re {
+			return e, ok
+		}
+		lm.list.Lock()
+		lm.list.unlink(e)
+		lm.list.Unlock()
+		removed = true
+		return nil, false
+	})
+	return removed
+}
+
+func (l *linkedList[k, v]) append(e *linkedEntry[k, v]) {
+	e.linked = true
+	e.before = l.tail
+	e.after = nil
+	if l.tail == nil {
+		l.head = e
+	} else {
+		l.tail.after = e
+	}
+	l.tail = e
+}
+
+func (l *linkedList[k, v]) unlink(e *linkedEntry[k, v]) {
+	if e.before == nil {
+		l.head = e.after
+	} else {
+		e.before.after = e.after
+	}
+	if e.after == nil {
+		l.tail = e.before
+	} else {
+		e.after.before = e.before
+	}
+	e.before = nil
+	e.after = nil
+	e.linked = false
+}
+
+// replace puts ne into the position of e, or to the end in access order.
+func (l *linkedList[k, v]) replace(e, ne *linkedEntry[k, v]) {
+	if l.accessOrder {
+		l.unlink(e)
+		l.append(ne)
+		return
+	}
+	ne.linked = true
+	ne.before = e.before
+	ne.after = e.after
+	if e.before == nil {
+		l.head = ne
+	} else {
+		e.before.after = ne
+	}
+	if e.after == nil {
+		l.tail = ne
+	} else {
+		e.after.before = ne
+	}
+	e.before = nil
+	e.after = nil
+	e.linked = false
+}
@@ -0,0 +1,107 @@
+package hashmap
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestLinkedConcurrentHashMap_InsertionOrder(t *testing.T) {
+	lm := NewLinkedString[int](false)
+	for i := 0; i < 1_000; i++ {
+		lm.Put(strconv.Itoa(i), i)
+	}
+	lm.Put("0", -1)
+	lm.Get("1")
+	lm.Remove("2")
+	var vals []int
+	lm.Range(func(key string, val int) bool {
+		vals = append(vals, val)
+		return true
+	})
+	if len(vals) != 999 || vals[0] != -1 || vals[1] != 1 || vals[2] != 3 || vals[998] != 999 {
+		t.Logf("values: %v", vals[:3])
+		t.FailNow()
+	}
+	if k, v, ok := lm.First(); !ok || k != "0" || v != -1 {
+		t.Logf("key: %s, value: %v, ok: %v", k, v, ok)
+		t.FailNow()
+	}
+	if k, v, ok := lm.Last(); !ok || k != "999" || v != 999 {
+		t.Logf("key: %s, value: %v, ok: %v", k, v, ok)
+		t.FailNow()
+	}
+}
+
+func TestLinkedConcurrentHashMap_AccessOrder(t *testing.T) {
+	lm := NewLinkedString[int](true)
+	for i := 0; i < 10; i++ {
+		lm.Put(strconv.Itoa(i), i)
+	}
+	lm.Get("3")
+	lm.Put("5", 5)
+	var keys []string
+	lm.Range(func(key string, val int) bool {
+		keys = append(keys, key)
+		return true
+	})
+	expected := []string{"0", "1", "2", "4", "6", "7", "8", "9", "3", "5"}
+	for i := range expected {
+		if keys[i] != expected[i] {
+			t.Logf("keys: %v", keys)
+			t.FailNow()
+		}
+	}
+}
+
+func TestLinkedConcurrentHashMap_RemoveEldest(t *testing.T) {
+	lm := NewLinkedString[int](true)
+	lm.SetRemoveEldestFunc(func(key string, val int, size int) bool {
+		return size > 100
+	})
+	for i := 0; i < 1_000; i++ {
+		lm.Put(strconv.Itoa(i), i)
+		lm.Get("0")
+	}
+	if lm.Size() != 100 || !lm.Contains("0") || lm.Contains("1") {
+		t.Logf("size: %d", lm.Size())
+		t.FailNow()
+	}
+	if k, v, ok := lm.RemoveEldest(); !ok || k != "901" || v != 901 {
+		t.Logf("key: %s, value: %v, ok: %v", k, v, ok)
+		t.FailNow()
+	}
+	if lm.Size() != 99 {
+		t.Logf("size: %d", lm.Size())
+		t.FailNow()
+	}
+}
+
+func TestLinkedConcurrentHashMap_ConcurrentlyPut_Remove(t *testing.T) {
+	lm := NewLinkedString[int](true)
+	var wg sync.WaitGroup
+	putRemove := func(from, to int) {
+		defer wg.Done()
+		for i := from; i < to; i++ {
+			lm.Put(strconv.Itoa(i), i)
+			lm.Get(strconv.Itoa(i - 1))
+			if i%2 == 0 {
+				lm.Remove(strconv.Itoa(i))
+			}
+		}
+	}
+	wg.Add(3)
+	go putRemove(0, 10_000)
+	go putRemove(5_000, 15_000)
+	go putRemove(10_000, 20_000)
+	wg.Wait()
+	count := 0
+	lm.Range(func(key string, val int) bool {
+		count++
+		return true
+	})
+	if count != lm.Size() || count != 10_000 {
+		t.Logf("count: %d, size: %d", count, lm.Size())
+		t.FailNow()
+	}
+}
@@ -0,0 +1,235 @@
+package hashmap
+
+import (
+	"sync"
+	"time"
+)
+
+// HLC is a hybrid logical clock timestamp, the wall clock time in nanoseconds
+// and a logical counter ordering the events within the same wall clock time.
+type HLC struct {
+	Wall    int64  `json:"wall"`
+	Logical uint32 `json:"logical"`
+}
+
+// Less returns if the timestamp is before the other timestamp.
+func (t HLC) Less(o HLC) bool {
+	return t.Wall < o.Wall || t.Wall == o.Wall && t.Logical < o.Logical
+}
+
+// hlc generates hybrid logical clock timestamps, which never go backwards
+// and are after every timestamp observed from other nodes.
+type hlc struct {
+	sync.Mutex
+	last HLC
+	now  func() int64
+}
+
+func (c *hlc) tick() HLC {
+	c.Lock()
+	defer c.Unlock()
+	if wall := c.now(); wall > c.last.Wall {
+		c.last = HLC{Wall: wall}
+	} else {
+		c.last.Logical++
+	}
+	return c.last
+}
+
+func (c *hlc) observe(t HLC) {
+	c.Lock()
+	defer c.Unlock()
+	if c.last.Less(t) {
+		c.last = t
+	}
+}
+
+func (c *hlc) current() HLC {
+	c.Lock()
+	defer c.Unlock()
+	return c.last
+}
+
+// LWWEntry is the state of a key in LWWMap, a value or a tombstone, with the timestamp and node of its write.
+type LWWEntry[v any] struct {
+	Value     v      `json:"value,omitempty"`
+	Timestamp HLC    `json:"timestamp"`
+	Node      string `json:"node"`
+	Deleted   bool   `json:"deleted,omitempty"`
+}
+
+// wins returns if the entry is written after the other entry,
+// ties of timestamps are broken by node so every node picks the same entry.
+func (e *LWWEntry[v]) wins(o *LWWEntry[v]) bool {
+	if e.Timestamp != o.Timestamp {
+		return o.Timestamp.Less(e.Timestamp)
+	}
+	return e.Node > o.Node
+}
+
+// lwwEntry is an entry with the local timestamp it is applied at, which Delta filters on,
+// so that entries relayed from other nodes are exported even if they are written before since.
+type lwwEntry[v any] struct {
+	LWWEntry[v]
+	applied HLC
+}
+
+// LWWDelta is an entry of LWWMap exported by Delta, to be imported by other nodes.
+type LWWDelta[k, v any] struct {
+	Key   k           `json:"key"`
+	Entry LWWEntry[v] `json:"entry"`
+}
+
+// LWWMap thread-safe last-writer-wins map, which accepts writes on multiple nodes and converges once they exchange their states.
+// Every write is stamped with a hybrid logical clock timestamp and the node id, and the latest write of a key wins regardless
+// of the order the writes are merged in. Removals are kept as tombstones until they are collected by GC.
+type LWWMap[k, v any] struct {
+	m     ConcurrentHashMap[k, lwwEntry[v]]
+	node  string
+	clock *hlc
+}
+
+// NewLWWMap returns LWWMap of the given node with default capacity.
+func NewLWWMap[k Hasher, v any](node string) LWWMap[k, v] {
+	return newLWWMap(node, New[k, lwwEntry[v]]())
+}
+
+// NewStringLWWMap returns string type key LWWMap of the given node with default capacity.
+func NewStringLWWMap[v any](node string) LWWMap[string, v] {
+	return newLWWMap(node, NewString[lwwEntry[v]]())
+}
+
+// NewLWWMapWithCapAndFuncs returns LWWMap of the given node with the given capacity and funcs.
+func NewLWWMapWithCapAndFuncs[k, v any](node string, capacity int, hf HashFunc[k], ef EqualsFunc[k]) (LWWMap[k, v], error) {
+	m, err := NewWithCapAndFuncs[k, lwwEntry[v]](capacity, hf, ef)
+	if err != nil {
+		return LWWMap[k, v]{}, err
+	}
+	return newLWWMap(node, m), nil
+}
+
+func newLWWMap[k, v any](node string, m ConcurrentHashMap[k, lwwEntry[v]]) LWWMap[k, v] {
+	return LWWMap[k, v]{
+		m:    m,
+		node: node,
+		clock: &hlc{now: func() int64 {
+			return time.Now().UnixNano()
+		}},
+	}
+}
+
+// Node returns the node id of the map.
+func (lm *LWWMap[k, v]) Node() string {
+	return lm.node
+}
+
+// Clock returns the latest timestamp generated or observed by the map.
+func (lm *LWWMap[k, v]) Clock() HLC {
+	return lm.clock.current()
+}
+
+// Put maps the given key to the value.
+func (lm *LWWMap[k, v]) Put(key k, val v) {
+	lm.write(key, LWWEntry[v]{Value: val, Node: lm.node})
+}
+
+// Remove removes the entry mapped by the given key, leaving a tombstone, and returns if there was an entry.
+func (lm *LWWMap[k, v]) Remove(key k) bool {
+	return lm.write(key, LWWEntry[v]{Node: lm.node, Deleted: true})
+}
+
+// write stamps the entry under the bucket lock, so that writes of a key are stamped in the order they are applied.
+func (lm *LWWMap[k, v]) write(key k, e LWWEntry[v]) bool {
+	existed := false
+	lm.m.Compute(key, func(old lwwEntry[v], ok bool) (lwwEntry[v], bool) {
+		existed = ok && !old.Deleted
+		// merged timestamps are observed before they are saved, so the new timestamp is after old
+		e.Timestamp = lm.clock.tick()
+		return lwwEntry[v]{LWWEntry: e, applied: e.Timestamp}, true
+	})
+	return existed
+}
+
+// Get returns value of the entry mapped by given key.
+func (lm *LWWMap[k, v]) Get(key k) (v, bool) {
+	e, ok := lm.m.Get(key)
+	if !ok || e.Deleted {
+		return *new(v), false
+	}
+	return e.Value, true
+}
+
+// Contains returns if there is an entry mapped by the given key.
+func (lm *LWWMap[k, v]) Contains(key k) bool {
+	_, ok := lm.Get(key)
+	return ok
+}
+
+// Range calls fn for each entry, excluding tombstones, until fn returns false.
+func (lm *LWWMap[k, v]) Range(fn func(key k, val v) bool) {
+	lm.m.Range(func(key k, e lwwEntry[v]) bool {
+		if e.Deleted {
+			return true
+		}
+		return fn(key, e.Value)
+	})
+}
+
+// Size returns the count of entries, excluding tombstones.
+func (lm *LWWMap[k, v]) Size() int {
+	size := 0
+	lm.Range(func(key k, val v) bool {
+		size++
+		return true
+	})
+	return size
+}
+
+// Merge merges the entries and tombstones of the other map into the map.
+func (lm *LWWMap[k, v]) Merge(other *LWWMap[k, v]) {
+	other.m.Range(func(key k, e lwwEntry[v]) bool {
+		lm.merge(key, e.LWWEntry)
+		return true
+	})
+}
+
+// Delta returns the entries and tombstones written to or merged into the map after the given timestamp
+// of its clock, including the entries merged from other nodes which were written earlier.
+// since is a timestamp returned by Clock of this map, a node which has imported a delta can take the Clock
+// of this map before exporting it, and pass it as since of the next delta to catch up.
+func (lm *LWWMap[k, v]) Delta(since HLC) []LWWDelta[k, v] {
+	var delta []LWWDelta[k, v]
+	lm.m.Range(func(key k, e lwwEntry[v]) bool {
+		if since.Less(e.applied) {
+			delta = append(delta, LWWDelta[k, v]{Key: key, Entry: e.LWWEntry})
+		}
+		return true
+	})
+	return delta
+}
+
+// Import merges the given delta into the map.
+func (lm *LWWMap[k, v]) Import(delta []LWWDelta[k, v]) {
+	for _, d := range delta {
+		lm.merge(d.Key, d.Entry)
+	}
+}
+
+func (lm *LWWMap[k, v]) merge(key k, e LWWEntry[v]) {
+	lm.clock.observe(e.Timestamp)
+	lm.m.Compute(key, func(old lwwEntry[v], ok bool) (lwwEntry[v], bool) {
+		if ok && !e.wins(&old.LWWEntry) {
+			return old, true
+		}
+		return lwwEntry[v]{LWWEntry: e, applied: lm.clock.tick()}, true
+	})
+}
+
+// GC removes the tombstones written before the given timestamp and returns the count of removed tombstones.
+// A removed key can be brought back by merging an older write of it, so before should be older than
+// the writes that are still to be merged from other nodes.
+func (lm *LWWMap[k, v]) GC(before HLC) int {
+	return lm.m.RemoveIf(func(key k, e lwwEntry[v]) bool {
+		return e.Deleted && e.Timestamp.Less(before)
+	})
+}
@@ -0,0 +1,220 @@
+package hashmap
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestLWWMap(t *testing.T) {
+	lm := NewStringLWWMap[int]("a")
+	lm.Put("x", 1)
+	lm.Put("x", 2)
+	if v, ok := lm.Get("x"); !ok || v != 2 {
+		t.Logf("value: %d, ok: %v", v, ok)
+		t.FailNow()
+	}
+	if !lm.Remove("x") || lm.Remove("x") {
+		t.FailNow()
+	}
+	if lm.Contains("x") || lm.Size() != 0 {
+		t.FailNow()
+	}
+	if delta := lm.Delta(HLC{}); len(delta) != 1 || !delta[0].Entry.Deleted {
+		t.Logf("delta: %+v", delta)
+		t.FailNow()
+	}
+}
+
+func TestLWWMap_Merge(t *testing.T) {
+	a := NewStringLWWMap[int]("a")
+	b := NewStringLWWMap[int]("b")
+	c := NewStringLWWMap[int]("c")
+	a.Put("x", 1)
+	b.Put("x", 2)
+	a.Put("y", 1)
+	b.Merge(&a)
+	b.Remove("y")
+	c.Put("z", 3)
+	c.Put("x", 3)
+	ab, ba := NewStringLWWMap[int]("ab"), NewStringLWWMap[int]("ba")
+	for _, m := range []*LWWMap[string, int]{&a, &b, &c} {
+		ab.Merge(m)
+	}
+	for _, m := range []*LWWMap[string, int]{&c, &b, &a} {
+		ba.Merge(m)
+	}
+	for _, m := range []*LWWMap[string, int]{&ab, &ba} {
+		if v, ok := m.Get("x"); !ok || v != 3 {
+			t.Logf("x: %d, ok: %v", v, ok)
+			t.FailNow()
+		}
+		if m.Contains("y") {
+			t.Log("removed key is merged")
+			t.FailNow()
+		}
+		if m.Size() != 2 {
+			t.Logf("size: %d", m.Size())
+			t.FailNow()
+		}
+	}
+}
+
+func TestLWWMap_Merge_Tie(t *testing.T) {
+	a := NewStringLWWMap[int]("a")
+	b := NewStringLWWMap[int]("b")
+	now := func() int64 {
+		return 42
+	}
+	a.clock.now, b.clock.now = now, now
+	a.Put("x", 1)
+	b.Put("x", 2)
+	a.Merge(&b)
+	b.Merge(&a)
+	va, _ := a.Get("x")
+	vb, _ := b.Get("x")
+	if va != 2 || vb != 2 {
+		t.Logf("a: %d, b: %d", va, vb)
+		t.FailNow()
+	}
+}
+
+func TestLWWMap_Clock(t *testing.T) {
+	a := NewStringLWWMap[int]("a")
+	b := NewStringLWWMap[int]("b")
+	b.clock.now = func() int64 {
+		return 1
+	}
+	a.Put("x", 1)
+	b.Merge(&a)
+	b.Put("x", 2)
+	a.Merge(&b)
+	if v, _ := a.Get("x"); v != 2 {
+		t.Log("write after merge is lost because of clock skew")
+		t.FailNow()
+	}
+}
+
+func TestLWWMap_DeltaImport(t *testing.T) {
+	a := NewStringLWWMap[int]("a")
+	b := NewStringLWWMap[int]("b")
+	for i := 0; i < 100; i++ {
+		a.Put(strconv.Itoa(i), i)
+	}
+	since := a.Clock()
+	b.Import(a.Delta(HLC{}))
+	for i := 0; i < 10; i++ {
+		a.Remove(strconv.Itoa(i))
+	}
+	a.Put("0", -1)
+	delta := a.Delta(since)
+	if len(delta) != 10 {
+		t.Logf("delta: %d", len(delta))
+		t.FailNow()
+	}
+	b.Import(delta)
+	if b.Size() != 91 {
+		t.Logf("size: %d", b.Size())
+		t.FailNow()
+	}
+	if v, _ := b.Get("0"); v != -1 {
+		t.Logf("value: %d", v)
+		t.FailNow()
+	}
+}
+
+func TestLWWMap_GC(t *testing.T) {
+	lm := NewStringLWWMap[int]("a")
+	lm.Put("x", 1)
+	lm.Put("y", 1)
+	lm.Remove("x")
+	before := lm.Clock()
+	lm.Remove("y")
+	if n := lm.GC(before); n != 0 {
+		t.Logf("collected: %d", n)
+		t.FailNow()
+	}
+	lm.Put("z", 1)
+	if n := lm.GC(lm.Clock()); n != 2 {
+		t.Logf("collected: %d", n)
+		t.FailNow()
+	}
+	if lm.Size() != 1 || len(lm.Delta(HLC{})) != 1 {
+		t.FailNow()
+	}
+}
+
+func TestLWWMap_ConcurrentlyMerge(t *testing.T) {
+	nodes := make([]LWWMap[string, int], 4)
+	for i := range nodes {
+		nodes[i] = NewStringLWWMap[int](strconv.Itoa(i))
+	}
+	var wg sync.WaitGroup
+	for i := range nodes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1_000; j++ {
+				key := strconv.Itoa(j % 50)
+				if j%5 == 0 {
+					nodes[i].Remove(key)
+				} else {
+					nodes[i].Put(key, i*1_000+j)
+				}
+				if j%100 == 0 {
+					nodes[i].Merge(&nodes[(i+1)%len(nodes)])
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	for round := 0; round < 2; round++ {
+		for i := range nodes {
+			for j := range nodes {
+				nodes[i].Merge(&nodes[j])
+			}
+		}
+	}
+	want := nodes[0].Delta(HLC{})
+	for i := 1; i < len(nodes); i++ {
+		for _, d := range want {
+			e, ok := nodes[i].m.Get(d.Key)
+			if !ok || e.LWWEntry != d.Entry {
+				t.Logf("node: %d, key: %s, entry: %+v, want: %+v", i, d.Key, e.LWWEntry, d.Entry)
+				t.FailNow()
+			}
+		}
+	}
+}
+
+func TestLWWMap_DeltaImport_Relay(t *testing.T) {
+	a := NewStringLWWMap[int]("a")
+	b := NewStringLWWMap[int]("b")
+	c := NewStringLWWMap[int]("c")
+	c.clock.now = func() int64 {
+		return 1
+	}
+	c.Put("lagging", 1)
+	a.Put("x", 1)
+	since := a.Clock()
+	b.Import(a.Delta(HLC{}))
+	a.Import(c.Delta(HLC{}))
+	a.Put("y", 1)
+	b.Import(a.Delta(since))
+	for _, key := range []string{"x", "y", "lagging"} {
+		if !b.Contains(key) {
+			t.Logf("key: %s", key)
+			t.FailNow()
+		}
+	}
+	since = a.Clock()
+	if delta := a.Delta(since); len(delta) != 0 {
+		t.Logf("delta: %+v", delta)
+		t.FailNow()
+	}
+	a.Import(c.Delta(HLC{}))
+	if delta := a.Delta(since); len(delta) != 0 {
+		t.Log("entry which is already applied is exported again")
+		t.FailNow()
+	}
+}
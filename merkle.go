@@ -0,0 +1,272 @@
+package hashmap
+
+import (
+	"errors"
+	"hash/fnv"
+	"sync/atomic"
+)
+
+// ErrMerkleMismatch is returned when two Merkle trees cannot be compared,
+// because their maps have different capacities or their trees have different leaf counts.
+var ErrMerkleMismatch = errors.New("merkle trees are not comparable")
+
+// KeyHashFunc hashes keys for the Merkle tree, equal keys must have equal hashes.
+// It is separate from the HashFunc of the map, whose 32 bits are too few to tell keys apart.
+type KeyHashFunc[k any] func(key k) uint64
+
+// ValueHashFunc hashes values for the Merkle tree, equal values must have equal hashes.
+type ValueHashFunc[v any] func(val v) uint64
+
+// BucketRange is the range of buckets [From, To) covered by a node of the Merkle tree.
+type BucketRange struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+// Merkle is a hash tree over the bucket ranges of a map, updated incrementally as the map changes.
+// The digest of a leaf is the sum of the digests of the entries in its bucket range,
+// and the digest of an inner node is the sum of the digests of its children,
+// so a change updates the digests on the path from its leaf to the root without locking.
+// Two maps with the same capacity and hash func have equal roots if they have equal entries,
+// differing ranges are found by descending into the children with differing digests.
+type Merkle[k, v any] struct {
+	m        *ConcurrentHashMap[k, v]
+	khf      KeyHashFunc[k]
+	vhf      ValueHashFunc[v]
+	leaves   int
+	digests  []uint64
+	attached []bool
+}
+
+// NewMerkle returns Merkle tree over the given map with the given count of leaves,
+// which is rounded up to a power of two and limited by the capacity of the map.
+func NewMerkle[k, v any](m *ConcurrentHashMap[k, v], leaves int, khf KeyHashFunc[k], vhf ValueHashFunc[v]) (*Merkle[k, v], error) {
+	if leaves <= 0 {
+		return nil, errors.New("leaves must be positive value")
+	}
+	if khf == nil || vhf == nil {
+		return nil, errors.New("key and value hash funcs cannot be nil")
+	}
+	n := 1
+	for n < leaves && n < int(m.capacity) {
+		n <<= 1
+	}
+	mt := &Merkle[k, v]{
+		m:        m,
+		khf:      khf,
+		vhf:      vhf,
+		leaves:   n,
+		digests:  make([]uint64, 2*n),
+		attached: make([]bool, m.capacity),
+	}
+	m.obs.add(mt)
+	// buckets are attached one by one, changes of a bucket are ignored by the observer until it is attached
+	for i, b := range m.table {
+		b.Lock()
+		var d uint64
+		b.each(func(n *node[k, v]) bool {
+			d += mt.digest(n.key, n.value)
+			return true
+		})
+		mt.add(uint32(i), d)
+		mt.attached[i] = true
+		b.Unlock()
+	}
+	return mt, nil
+}
+
+// NewStringMerkle returns Merkle tree over the given string type key map, hashing keys by 64-bit FNV-1a.
+func NewStringMerkle[v any](m *ConcurrentHashMap[string, v], leaves int, vhf ValueHashFunc[v]) (*Merkle[string, v], error) {
+	return NewMerkle(m, leaves, hashString64, vhf)
+}
+
+func hashString64(key string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	return h.Sum64()
+}
+
+// Close stops updating the tree.
+func (mt *Merkle[k, v]) Close() {
+	mt.m.obs.delete(mt)
+}
+
+// Leaves returns the count of leaves of the tree.
+func (mt *Merkle[k, v]) Leaves() int {
+	return mt.leaves
+}
+
+// Root returns the digest of the root of the tree.
+func (mt *Merkle[k, v]) Root() uint64 {
+	return atomic.LoadUint64(&mt.digests[1])
+}
+
+// Digest returns the digest of the node at the given index of the given level, the root is at level zero.
+// It can be used to compare trees node by node across processes.
+// It returns false if there is no such node, levels range up to log2 of Leaves and indices up to 1<<level.
+func (mt *Merkle[k, v]) Digest(level, index int) (uint64, bool) {
+	i, ok := mt.node(level, index)
+	if !ok {
+		return 0, false
+	}
+	return atomic.LoadUint64(&mt.digests[i]), true
+}
+
+// Range returns the bucket range covered by the node at the given index of the given level.
+// It returns false if there is no such node.
+func (mt *Merkle[k, v]) Range(level, index int) (BucketRange, bool) {
+	i, ok := mt.node(level, index)
+	if !ok {
+		return BucketRange{}, false
+	}
+	return mt.bucketRange(i), true
+}
+
+// node returns the position of the node at the given index of the given level in digests.
+func (mt *Merkle[k, v]) node(level, index int) (int, bool) {
+	// leaves is at most the capacity of the map, which fits in 32 bits
+	if level < 0 || level > 32 || 1<<level > mt.leaves || index < 0 || index >= 1<<level {
+		return 0, false
+	}
+	return (1 << level) + index, true
+}
+
+// DiffRanges returns the bucket ranges of the leaves whose digests differ between the trees.
+func (mt *Merkle[k, v]) DiffRanges(other *Merkle[k, v]) ([]BucketRange, error) {
+	if mt.m.capacity != other.m.capacity || mt.leaves != other.leaves {
+		return nil, ErrMerkleMismatch
+	}
+	var ranges []BucketRange
+	var descend func(i int)
+	descend = func(i int) {
+		if atomic.LoadUint64(&mt.digests[i]) == atomic.LoadUint64(&other.digests[i]) {
+			return
+		}
+		if i >= mt.leaves {
+			ranges = append(ranges, mt.bucketRange(i))
+			return
+		}
+		descend(2 * i)
+		descend(2*i + 1)
+	}
+	descend(1)
+	return ranges, nil
+}
+
+// DiffKeys returns the keys whose entries differ between the maps of the trees,
+// the keys which are in only one of the maps or mapped to values with different hashes.
+// The maps must have the same hash and equals funcs.
+func (mt *Merkle[k, v]) DiffKeys(other *Merkle[k, v]) ([]k, error) {
+	ranges, err := mt.DiffRanges(other)
+	if err != nil {
+		return nil, err
+	}
+	var keys []k
+	for _, r := range ranges {
+		a := mt.entries(r)
+		b := other.entries(r)
+		for _, e := range a {
+			if !containsEntry(b, e, mt.m.ef) {
+				keys = append(keys, e.key)
+			}
+		}
+		for _, e := range b {
+			if !containsKey(a, e.key, mt.m.ef) {
+				keys = append(keys, e.key)
+			}
+		}
+	}
+	return keys, nil
+}
+
+type merkleEntry[k any] struct {
+	key  k
+	hash uint64
+}
+
+func containsEntry[k any](es []merkleEntry[k], e merkleEntry[k], ef EqualsFunc[k]) bool {
+	for _, o := range es {
+		if o.hash == e.hash && ef(o.key, e.key) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsKey[k any](es []merkleEntry[k], key k, ef EqualsFunc[k]) bool {
+	for _, o := range es {
+		if ef(o.key, key) {
+			return true
+		}
+	}
+	return false
+}
+
+func (mt *Merkle[k, v]) entries(r BucketRange) []merkleEntry[k] {
+	var es []merkleEntry[k]
+	mt.m.visit(r.From, r.To, func(n *node[k, v]) bool {
+		es = append(es, merkleEntry[k]{key: n.key, hash: mt.digest(n.key, n.value)})
+		return true
+	})
+	return es
+}
+
+func (mt *Merkle[k, v]) bucketRange(i int) BucketRange {
+	level := 0
+	for 1<<(level+1) <= i {
+		level++
+	}
+	width := mt.leaves >> level
+	from := (i - 1<<level) * width
+	// bucket i belongs to leaf i*leaves/capacity, so leaf j starts at the bucket ceil(j*capacity/leaves)
+	capacity := int(mt.m.capacity)
+	return BucketRange{
+		From: (from*capacity + mt.leaves - 1) / mt.leaves,
+		To:   ((from+width)*capacity + mt.leaves - 1) / mt.leaves,
+	}
+}
+
+// add adds d to the digests of the leaf of the bucket and of its ancestors.
+func (mt *Merkle[k, v]) add(bucket uint32, d uint64) {
+	if d == 0 {
+		return
+	}
+	i := int(uint64(bucket)*uint64(mt.leaves)/uint64(mt.m.capacity)) + mt.leaves
+	for ; i > 0; i >>= 1 {
+		atomic.AddUint64(&mt.digests[i], d)
+	}
+}
+
+// digest mixes the hashes of the key and the value of an entry.
+func (mt *Merkle[k, v]) digest(key k, val v) uint64 {
+	return fmix64(mt.khf(key)*0x9e3779b97f4a7c15 + fmix64(mt.vhf(val)))
+}
+
+func fmix64(x uint64) uint64 {
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
+
+func (mt *Merkle[k, v]) onPut(h uint32, key k, val v, old v, replaced bool) {
+	i := h % mt.m.capacity
+	if !mt.attached[i] {
+		return
+	}
+	d := mt.digest(key, val)
+	if replaced {
+		d -= mt.digest(key, old)
+	}
+	mt.add(i, d)
+}
+
+func (mt *Merkle[k, v]) onRemove(h uint32, key k, val v) {
+	i := h % mt.m.capacity
+	if !mt.attached[i] {
+		return
+	}
+	mt.add(i, -mt.digest(key, val))
+}
@@ -0,0 +1,184 @@
+package hashmap
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func intValueHash(val int) uint64 {
+	return uint64(val)
+}
+
+func newMerkleMaps(t *testing.T, leaves int) (ConcurrentHashMap[string, int], ConcurrentHashMap[string, int], *Merkle[string, int], *Merkle[string, int]) {
+	a, _ := NewStringWithCap[int](100)
+	b, _ := NewStringWithCap[int](100)
+	for i := 0; i < 1_000; i++ {
+		a.Put(strconv.Itoa(i), i)
+	}
+	ma, err := NewStringMerkle(&a, leaves, intValueHash)
+	if err != nil {
+		t.Logf("err: %v", err)
+		t.FailNow()
+	}
+	mb, _ := NewStringMerkle(&b, leaves, intValueHash)
+	for i := 999; i >= 0; i-- {
+		b.Put(strconv.Itoa(i), i)
+	}
+	return a, b, ma, mb
+}
+
+func TestMerkle(t *testing.T) {
+	a, b, ma, mb := newMerkleMaps(t, 16)
+	if ma.Root() != mb.Root() || ma.Root() == 0 {
+		t.Logf("roots: %d, %d", ma.Root(), mb.Root())
+		t.FailNow()
+	}
+	a.Put("1", -1)
+	a.Put("1000", 1000)
+	b.Remove("500")
+	if ma.Root() == mb.Root() {
+		t.FailNow()
+	}
+	ranges, err := ma.DiffRanges(mb)
+	if err != nil || len(ranges) == 0 || len(ranges) > 3 {
+		t.Logf("ranges: %v, err: %v", ranges, err)
+		t.FailNow()
+	}
+	keys, _ := ma.DiffKeys(mb)
+	diff := map[string]bool{}
+	for _, key := range keys {
+		diff[key] = true
+	}
+	if len(keys) != 3 || !diff["1"] || !diff["1000"] || !diff["500"] {
+		t.Logf("keys: %v", keys)
+		t.FailNow()
+	}
+	a.Put("1", 1)
+	a.Remove("1000")
+	b.Put("500", 500)
+	if ma.Root() != mb.Root() {
+		t.FailNow()
+	}
+	a.Clear()
+	if ma.Root() != 0 {
+		t.Logf("root: %d", ma.Root())
+		t.FailNow()
+	}
+}
+
+func TestMerkle_Range(t *testing.T) {
+	m, _ := NewStringWithCap[int](100)
+	mt, _ := NewStringMerkle(&m, 16, intValueHash)
+	if r, ok := mt.Range(0, 0); !ok || r.From != 0 || r.To != 100 {
+		t.Logf("range: %v", r)
+		t.FailNow()
+	}
+	next := 0
+	for i := 0; i < mt.Leaves(); i++ {
+		r, ok := mt.Range(4, i)
+		if !ok || r.From != next || r.To <= r.From {
+			t.Logf("leaf: %d, range: %v", i, r)
+			t.FailNow()
+		}
+		for b := r.From; b < r.To; b++ {
+			if leaf := b * mt.Leaves() / 100; leaf != i {
+				t.Logf("bucket: %d, leaf: %d, range leaf: %d", b, leaf, i)
+				t.FailNow()
+			}
+		}
+		next = r.To
+	}
+	if next != 100 {
+		t.FailNow()
+	}
+	m.Put("a", 1)
+	sum := uint64(0)
+	for i := 0; i < 2; i++ {
+		d, _ := mt.Digest(1, i)
+		sum += d
+	}
+	if sum != mt.Root() {
+		t.FailNow()
+	}
+}
+
+func TestMerkle_Digest_OutOfRange(t *testing.T) {
+	m, _ := NewStringWithCap[int](100)
+	mt, _ := NewStringMerkle(&m, 4, intValueHash)
+	m.Put("a", 1)
+	if d, ok := mt.Digest(0, 0); !ok || d != mt.Root() {
+		t.FailNow()
+	}
+	if _, ok := mt.Digest(2, 3); !ok {
+		t.FailNow()
+	}
+	for _, node := range [][2]int{{3, 0}, {5, 0}, {64, 0}, {-1, 0}, {1, 2}, {2, -1}, {0, 1}} {
+		if _, ok := mt.Digest(node[0], node[1]); ok {
+			t.Logf("level: %d, index: %d", node[0], node[1])
+			t.FailNow()
+		}
+		if _, ok := mt.Range(node[0], node[1]); ok {
+			t.Logf("level: %d, index: %d", node[0], node[1])
+			t.FailNow()
+		}
+	}
+}
+
+func TestMerkle_Mismatch(t *testing.T) {
+	a, _ := NewStringWithCap[int](100)
+	b, _ := NewStringWithCap[int](50)
+	ma, _ := NewStringMerkle(&a, 16, intValueHash)
+	mb, _ := NewStringMerkle(&b, 16, intValueHash)
+	if _, err := ma.DiffKeys(mb); err != ErrMerkleMismatch {
+		t.Logf("err: %v", err)
+		t.FailNow()
+	}
+}
+
+func TestMerkle_ConcurrentlyAttach(t *testing.T) {
+	m, _ := NewStringWithCap[int](64)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 10_000; i++ {
+			key := strconv.Itoa(i % 500)
+			if i%3 == 0 {
+				m.Remove(key)
+			} else {
+				m.Put(key, i)
+			}
+		}
+	}()
+	mt, _ := NewStringMerkle(&m, 8, intValueHash)
+	wg.Wait()
+	fresh, _ := NewStringMerkle(&m, 8, intValueHash)
+	if mt.Root() != fresh.Root() {
+		t.Logf("roots: %d, %d", mt.Root(), fresh.Root())
+		t.FailNow()
+	}
+}
+
+func TestMerkle_KeyHashCollision(t *testing.T) {
+	hf := func(key string) uint32 {
+		return 7
+	}
+	a, _ := NewWithCapAndFuncs[string, int](16, hf, equalsString)
+	b, _ := NewWithCapAndFuncs[string, int](16, hf, equalsString)
+	a.Put("x", 1)
+	b.Put("y", 1)
+	ma, _ := NewStringMerkle(&a, 4, intValueHash)
+	mb, _ := NewStringMerkle(&b, 4, intValueHash)
+	if ma.Root() == mb.Root() {
+		t.Log("different keys with colliding hashes have equal roots")
+		t.FailNow()
+	}
+	if keys, _ := ma.DiffKeys(mb); len(keys) != 2 {
+		t.Logf("keys: %v", keys)
+		t.FailNow()
+	}
+	if _, err := NewMerkle[string, int](&a, 4, nil, intValueHash); err == nil {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,113 @@
+package hashmap
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// ConcurrentMultiMap thread-safe map associating each key with a collection of values.
+// The value collection of a key is modified atomically under the bucket lock of the key.
+type ConcurrentMultiMap[k, v any] struct {
+	m     ConcurrentHashMap[k, []v]
+	vef   EqualsFunc[v]
+	count *int64
+}
+
+// NewMultiMap returns ConcurrentMultiMap with default capacity.
+func NewMultiMap[k Hasher, v comparable]() ConcurrentMultiMap[k, v] {
+	return ConcurrentMultiMap[k, v]{
+		m:     New[k, []v](),
+		vef:   equalsComparable[v],
+		count: new(int64),
+	}
+}
+
+// NewStringMultiMap returns string type key ConcurrentMultiMap with default capacity.
+func NewStringMultiMap[v comparable]() ConcurrentMultiMap[string, v] {
+	return ConcurrentMultiMap[string, v]{
+		m:     NewString[[]v](),
+		vef:   equalsComparable[v],
+		count: new(int64),
+	}
+}
+
+// NewMultiMapWithCapAndFuncs returns ConcurrentMultiMap with the given capacity and funcs.
+// vef is used to compare values in RemoveValue.
+func NewMultiMapWithCapAndFuncs[k, v any](capacity int, hf HashFunc[k], ef EqualsFunc[k], vef EqualsFunc[v]) (cmm ConcurrentMultiMap[k, v], err error) {
+	if vef == nil {
+		err = errors.New("value equals func cannot be nil")
+		return
+	}
+	cmm.m, err = NewWithCapAndFuncs[k, []v](capacity, hf, ef)
+	cmm.vef = vef
+	cmm.count = new(int64)
+	return
+}
+
+func equalsComparable[v comparable](v1, v2 v) bool {
+	return v1 == v2
+}
+
+// Add appends the value to the values of the given key.
+func (mm *ConcurrentMultiMap[k, v]) Add(key k, val v) {
+	mm.m.Compute(key, func(vals []v, ok bool) ([]v, bool) {
+		atomic.AddInt64(mm.count, 1)
+		nv := make([]v, len(vals), len(vals)+1)
+		copy(nv, vals)
+		return append(nv, val), true
+	})
+}
+
+// RemoveValue removes the first occurrence of the value from the values of the given key.
+// The key is removed when it has no values left. It returns whether the value is removed.
+func (mm *ConcurrentMultiMap[k, v]) RemoveValue(key k, val v) bool {
+	removed := false
+	mm.m.Compute(key, func(vals []v, ok bool) ([]v, bool) {
+		for i := range vals {
+			if mm.vef(vals[i], val) {
+				removed = true
+				atomic.AddInt64(mm.count, -1)
+				nv := make([]v, 0, len(vals)-1)
+				nv = append(nv, vals[:i]...)
+				nv = append(nv, vals[i+1:]...)
+				return nv, len(nv) > 0
+			}
+		}
+		return vals, ok
+	})
+	return removed
+}
+
+// Get returns a copy of the values of the given key.
+func (mm *ConcurrentMultiMap[k, v]) Get(key k) []v {
+	vals, _ := mm.m.Get(key)
+	if len(vals) == 0 {
+		return nil
+	}
+	// value collections are replaced on every modification, it is safe to copy outside the lock
+	cp := make([]v, len(vals))
+	copy(cp, vals)
+	return cp
+}
+
+// RemoveAll removes the given key with all its values and returns the removed values.
+func (mm *ConcurrentMultiMap[k, v]) RemoveAll(key k) []v {
+	vals, _ := mm.m.Remove(key)
+	atomic.AddInt64(mm.count, -int64(len(vals)))
+	return vals
+}
+
+// Contains returns if there is any value for the given key.
+func (mm *ConcurrentMultiMap[k, v]) Contains(key k) bool {
+	return mm.m.Contains(key)
+}
+
+// Size returns the count of keys in the map.
+func (mm *ConcurrentMultiMap[k, v]) Size() int {
+	return mm.m.Size()
+}
+
+// ValueCount returns the total count of values in the map.
+func (mm *ConcurrentMultiMap[k, v]) ValueCount() int {
+	return int(atomic.LoadInt64(mm.count))
+}
@@ -0,0 +1,88 @@
+package hashmap
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentMultiMap(t *testing.T) {
+	mm := NewStringMultiMap[int]()
+	for i := 0; i < 1_000; i++ {
+		mm.Add(strconv.Itoa(i%10), i)
+	}
+	if mm.Size() != 10 || mm.ValueCount() != 1_000 {
+		t.Logf("size: %d, value count: %d", mm.Size(), mm.ValueCount())
+		t.FailNow()
+	}
+	vals := mm.Get("3")
+	if len(vals) != 100 || vals[0] != 3 || vals[99] != 993 {
+		t.Logf("values: %v", vals)
+		t.FailNow()
+	}
+	if !mm.RemoveValue("3", 503) || mm.RemoveValue("3", 503) || mm.RemoveValue("3", 4) {
+		t.FailNow()
+	}
+	if len(mm.Get("3")) != 99 || mm.ValueCount() != 999 {
+		t.Logf("values: %d, value count: %d", len(mm.Get("3")), mm.ValueCount())
+		t.FailNow()
+	}
+	if removed := mm.RemoveAll("4"); len(removed) != 100 {
+		t.Logf("removed: %d", len(removed))
+		t.FailNow()
+	}
+	if mm.Contains("4") || mm.Size() != 9 || mm.ValueCount() != 899 {
+		t.Logf("size: %d, value count: %d", mm.Size(), mm.ValueCount())
+		t.FailNow()
+	}
+	mm.Add("5", 0)
+	for _, val := range mm.Get("5") {
+		mm.RemoveValue("5", val)
+	}
+	if mm.Contains("5") || mm.Get("5") != nil {
+		t.FailNow()
+	}
+}
+
+func TestConcurrentMultiMap_ConcurrentlyAdd(t *testing.T) {
+	mm := NewStringMultiMap[int]()
+	var wg sync.WaitGroup
+	add := func(from, to int) {
+		defer wg.Done()
+		for i := from; i < to; i++ {
+			mm.Add(strconv.Itoa(i%100), i)
+		}
+	}
+	wg.Add(3)
+	go add(0, 10_000)
+	go add(10_000, 20_000)
+	go add(20_000, 30_000)
+	wg.Wait()
+	if mm.ValueCount() != 30_000 {
+		t.Logf("value count: %d", mm.ValueCount())
+		t.FailNow()
+	}
+	for i := 0; i < 100; i++ {
+		if vals := mm.Get(strconv.Itoa(i)); len(vals) != 300 {
+			t.Logf("key: %d, values: %d", i, len(vals))
+			t.FailNow()
+		}
+	}
+}
+
+func TestNewMultiMapWithCapAndFuncs(t *testing.T) {
+	hf := func(key int) uint32 {
+		return uint32(key)
+	}
+	ef := func(k1, k2 int) bool {
+		return k1 == k2
+	}
+	_, err := NewMultiMapWithCapAndFuncs[int, int](32, hf, ef, ef)
+	if err != nil {
+		t.FailNow()
+	}
+	_, err = NewMultiMapWithCapAndFuncs[int, int](32, hf, ef, nil)
+	if err == nil {
+		t.FailNow()
+	}
+}
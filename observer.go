@@ -0,0 +1,46 @@
+package hashmap
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// observer is notified of the changes of the map, under the lock of the changed bucket.
+type observer[k, v any] interface {
+	onPut(h uint32, key k, val v, old v, replaced bool)
+	onRemove(h uint32, key k, val v)
+}
+
+// observers holds the registered observers, the list is replaced on each change
+// so that writers can load it without locking.
+type observers[k, v any] struct {
+	sync.Mutex
+	list atomic.Value
+}
+
+func (os *observers[k, v]) load() []observer[k, v] {
+	list, _ := os.list.Load().([]observer[k, v])
+	return list
+}
+
+func (os *observers[k, v]) add(o observer[k, v]) {
+	os.Lock()
+	defer os.Unlock()
+	list := os.load()
+	nl := make([]observer[k, v], len(list), len(list)+1)
+	copy(nl, list)
+	os.list.Store(append(nl, o))
+}
+
+func (os *observers[k, v]) delete(o observer[k, v]) {
+	os.Lock()
+	defer os.Unlock()
+	list := os.load()
+	nl := make([]observer[k, v], 0, len(list))
+	for _, lo := range list {
+		if lo != o {
+			nl = append(nl, lo)
+		}
+	}
+	os.list.Store(nl)
+}
@@ -0,0 +1,135 @@
+package hashmap
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// ForEachParallel calls fn for each entry in the map.
+// If the size of the map is at least parallelismThreshold, buckets are split into ranges
+// which are visited by a pool of goroutines, otherwise the map is visited by the calling goroutine.
+// Each bucket is visited under its read lock, fn must not modify the map.
+func (m *ConcurrentHashMap[k, v]) ForEachParallel(parallelismThreshold int, fn func(key k, val v)) {
+	m.parallel(parallelismThreshold, func(from, to int) {
+		m.visit(from, to, func(n *node[k, v]) bool {
+			fn(n.key, n.value)
+			return true
+		})
+	})
+}
+
+// ReduceValues returns the result of accumulating all values using the given reducer,
+// or false if the map is empty. Parallelism is applied as in ForEachParallel.
+func (m *ConcurrentHashMap[k, v]) ReduceValues(parallelismThreshold int, reducer func(v1, v2 v) v) (v, bool) {
+	return Reduce(m, parallelismThreshold, func(key k, val v) v {
+		return val
+	}, reducer)
+}
+
+// Search returns the first non-false result of applying fn on the entries, or false if there is none.
+// Once a result is found, all goroutines stop visiting the remaining entries.
+// Parallelism is applied as in ForEachParallel.
+func Search[k, v, r any](m *ConcurrentHashMap[k, v], parallelismThreshold int, fn func(key k, val v) (r, bool)) (r, bool) {
+	var found int32
+	var result r
+	m.parallel(parallelismThreshold, func(from, to int) {
+		m.visit(from, to, func(n *node[k, v]) bool {
+			if atomic.LoadInt32(&found) != 0 {
+				return false
+			}
+			res, ok := fn(n.key, n.value)
+			if ok && atomic.CompareAndSwapInt32(&found, 0, 1) {
+				result = res
+				return false
+			}
+			return true
+		})
+	})
+	return result, found != 0
+}
+
+// Reduce returns the result of accumulating the given transformation of all entries using the given reducer,
+// or false if the map is empty. Parallelism is applied as in ForEachParallel.
+func Reduce[k, v, r any](m *ConcurrentHashMap[k, v], parallelismThreshold int, transformer func(key k, val v) r, reducer func(r1, r2 r) r) (r, bool) {
+	var mu sync.Mutex
+	var result r
+	var ok bool
+	m.parallel(parallelismThreshold, func(from, to int) {
+		var partial r
+		var partialOk bool
+		m.visit(from, to, func(n *node[k, v]) bool {
+			t := transformer(n.key, n.value)
+			if partialOk {
+				partial = reducer(partial, t)
+			} else {
+				partial, partialOk = t, true
+			}
+			return true
+		})
+		if !partialOk {
+			return
+		}
+		mu.Lock()
+		if ok {
+			result = reducer(result, partial)
+		} else {
+			result, ok = partial, true
+		}
+		mu.Unlock()
+	})
+	return result, ok
+}
+
+// parallel splits the table into bucket ranges and calls fn for each range,
+// across a pool of goroutines if the size of the map is at least parallelismThreshold.
+func (m *ConcurrentHashMap[k, v]) parallel(parallelismThreshold int, fn func(from, to int)) {
+	workers := runtime.GOMAXPROCS(0)
+	if workers > int(m.capacity) {
+		workers = int(m.capacity)
+	}
+	if workers <= 1 || m.Size() < parallelismThreshold {
+		fn(0, int(m.capacity))
+		return
+	}
+	// more ranges than workers, so that workers finishing early take over the remaining ranges
+	ranges := workers * 4
+	if ranges > int(m.capacity) {
+		ranges = int(m.capacity)
+	}
+	step := (int(m.capacity) + ranges - 1) / ranges
+	var next int64 = -1
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for {
+				from := int(atomic.AddInt64(&next, 1)) * step
+				if from >= int(m.capacity) {
+					return
+				}
+				to := from + step
+				if to > int(m.capacity) {
+					to = int(m.capacity)
+				}
+				fn(from, to)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// visit calls fn for each node in the buckets of the given range under their read locks, until fn returns false.
+func (m *ConcurrentHashMap[k, v]) visit(from, to int, fn func(n *node[k, v]) bool) bool {
+	for i := from; i < to; i++ {
+		b := m.table[i]
+		b.RLock()
+		ok := b.each(fn)
+		b.RUnlock()
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
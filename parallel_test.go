@@ -0,0 +1,92 @@
+package hashmap
+
+import (
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+func TestConcurrentHashMap_ForEachParallel(t *testing.T) {
+	m, _ := NewStringWithCap[int](1_024)
+	for i := 0; i < 10_000; i++ {
+		m.Put(strconv.Itoa(i), i)
+	}
+	for _, threshold := range []int{1, 1_000_000} {
+		var count, sum int64
+		m.ForEachParallel(threshold, func(key string, val int) {
+			atomic.AddInt64(&count, 1)
+			atomic.AddInt64(&sum, int64(val))
+		})
+		if count != 10_000 || sum != 9_999*10_000/2 {
+			t.Logf("threshold: %d, count: %d, sum: %d", threshold, count, sum)
+			t.FailNow()
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	m, _ := NewStringWithCap[int](1_024)
+	for i := 0; i < 10_000; i++ {
+		m.Put(strconv.Itoa(i), i)
+	}
+	key, ok := Search(&m, 1, func(key string, val int) (string, bool) {
+		return key, val == 4_321
+	})
+	if !ok || key != "4321" {
+		t.Logf("key: %s, ok: %v", key, ok)
+		t.FailNow()
+	}
+	_, ok = Search(&m, 1, func(key string, val int) (string, bool) {
+		return key, val < 0
+	})
+	if ok {
+		t.FailNow()
+	}
+}
+
+func TestReduce(t *testing.T) {
+	m, _ := NewStringWithCap[int](1_024)
+	if _, ok := Reduce(&m, 1, func(key string, val int) int64 {
+		return int64(val)
+	}, func(r1, r2 int64) int64 {
+		return r1 + r2
+	}); ok {
+		t.FailNow()
+	}
+	for i := 0; i < 10_000; i++ {
+		m.Put(strconv.Itoa(i), i)
+	}
+	sum, ok := Reduce(&m, 1, func(key string, val int) int64 {
+		return int64(val)
+	}, func(r1, r2 int64) int64 {
+		return r1 + r2
+	})
+	if !ok || sum != 9_999*10_000/2 {
+		t.Logf("sum: %d, ok: %v", sum, ok)
+		t.FailNow()
+	}
+	greatest, ok := m.ReduceValues(1, func(v1, v2 int) int {
+		if v1 > v2 {
+			return v1
+		}
+		return v2
+	})
+	if !ok || greatest != 9_999 {
+		t.Logf("greatest: %d, ok: %v", greatest, ok)
+		t.FailNow()
+	}
+}
+
+func BenchmarkConcurrentHashMap_ForEachParallel(b *testing.B) {
+	m, _ := NewStringWithCap[int](1_024)
+	for i := 0; i < 100_000; i++ {
+		m.Put(strconv.Itoa(i), i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var sum int64
+		m.ForEachParallel(1, func(key string, val int) {
+			atomic.AddInt64(&sum, int64(val))
+		})
+	}
+}
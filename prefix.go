@@ -0,0 +1,197 @@
+package hashmap
+
+import (
+	stdsort "sort"
+	"strings"
+	"sync"
+)
+
+// PrefixIndex indexes the keys of a string type key map in a radix tree to serve prefix queries.
+// The index is kept in sync with the changes of the map until it is closed.
+type PrefixIndex[v any] struct {
+	m    *ConcurrentHashMap[string, v]
+	mu   sync.RWMutex
+	root *radixNode
+}
+
+type radixNode struct {
+	prefix   string
+	leaf     bool
+	children []*radixNode
+}
+
+// NewPrefixIndex builds a prefix index of the keys of the given map and registers it to the map,
+// so that it is updated atomically with the changes of the map.
+func NewPrefixIndex[v any](m *ConcurrentHashMap[string, v]) *PrefixIndex[v] {
+	pi := &PrefixIndex[v]{
+		m:    m,
+		root: &radixNode{},
+	}
+	m.obs.add(pi)
+	m.visit(0, int(m.capacity), func(n *node[string, v]) bool {
+		pi.mu.Lock()
+		pi.root.insert(n.key)
+		pi.mu.Unlock()
+		return true
+	})
+	return pi
+}
+
+// Close unregisters the index from the map, it is not updated anymore.
+func (pi *PrefixIndex[v]) Close() {
+	pi.m.obs.delete(pi)
+}
+
+// KeysWithPrefix returns the keys starting with the given prefix in lexicographical order.
+func (pi *PrefixIndex[v]) KeysWithPrefix(prefix string) []string {
+	var keys []string
+	pi.mu.RLock()
+	pi.root.walkPrefix(prefix, func(key string) {
+		keys = append(keys, key)
+	})
+	pi.mu.RUnlock()
+	return keys
+}
+
+// RangePrefix calls fn in lexicographical order for each entry whose key starts with the given prefix,
+// until fn returns false. Entries removed after the keys are collected are skipped.
+func (pi *PrefixIndex[v]) RangePrefix(prefix string, fn func(key string, val v) bool) {
+	for _, key := range pi.KeysWithPrefix(prefix) {
+		val, ok := pi.m.Get(key)
+		if !ok {
+			continue
+		}
+		if !fn(key, val) {
+			return
+		}
+	}
+}
+
+// RemovePrefix removes all entries whose key starts with the given prefix and returns the count of removed entries.
+func (pi *PrefixIndex[v]) RemovePrefix(prefix string) int {
+	return pi.m.RemoveAll(pi.KeysWithPrefix(prefix))
+}
+
+func (pi *PrefixIndex[v]) onPut(h uint32, key string, val v, old v, replaced bool) {
+	if replaced {
+		return
+	}
+	pi.mu.Lock()
+	pi.root.insert(key)
+	pi.mu.Unlock()
+}
+
+func (pi *PrefixIndex[v]) onRemove(h uint32, key string, val v) {
+	pi.mu.Lock()
+	pi.root.delete(key)
+	pi.mu.Unlock()
+}
+
+func (n *radixNode) child(c byte) (int, *radixNode) {
+	i := stdsort.Search(len(n.children), func(i int) bool {
+		return n.children[i].prefix[0] >= c
+	})
+	if i < len(n.children) && n.children[i].prefix[0] == c {
+		return i, n.children[i]
+	}
+	return i, nil
+}
+
+func (n *radixNode) insert(key string) {
+	for {
+		if len(key) == 0 {
+			n.leaf = true
+			return
+		}
+		i, c := n.child(key[0])
+		if c == nil {
+			n.children = append(n.children, nil)
+			copy(n.children[i+1:], n.children[i:])
+			n.children[i] = &radixNode{prefix: key, leaf: true}
+			return
+		}
+		l := commonPrefixLen(key, c.prefix)
+		if l < len(c.prefix) {
+			split := &radixNode{
+				prefix:   c.prefix[:l],
+				children: []*radixNode{c},
+			}
+			c.prefix = c.prefix[l:]
+			n.children[i] = split
+			c = split
+		}
+		key = key[l:]
+		n = c
+	}
+}
+
+func (n *radixNode) delete(key string) {
+	var parents []*radixNode
+	for len(key) > 0 {
+		_, c := n.child(key[0])
+		if c == nil || !strings.HasPrefix(key, c.prefix) {
+			return
+		}
+		parents = append(parents, n)
+		key = key[len(c.prefix):]
+		n = c
+	}
+	if !n.leaf {
+		return
+	}
+	n.leaf = false
+	for j := len(parents) - 1; j >= 0 && !n.leaf; j-- {
+		p := parents[j]
+		i, _ := p.child(n.prefix[0])
+		if len(n.children) == 0 {
+			p.children = append(p.children[:i], p.children[i+1:]...)
+		} else if len(n.children) == 1 {
+			c := n.children[0]
+			c.prefix = n.prefix + c.prefix
+			p.children[i] = c
+			return
+		} else {
+			return
+		}
+		n = p
+	}
+	// the root keeps an empty prefix, and a single child of it is not merged
+}
+
+func (n *radixNode) walkPrefix(prefix string, fn func(key string)) {
+	var path strings.Builder
+	for len(prefix) > 0 {
+		_, c := n.child(prefix[0])
+		if c == nil {
+			return
+		}
+		if strings.HasPrefix(c.prefix, prefix) {
+			n = c
+			prefix = ""
+		} else if strings.HasPrefix(prefix, c.prefix) {
+			prefix = prefix[len(c.prefix):]
+			n = c
+		} else {
+			return
+		}
+		path.WriteString(c.prefix)
+	}
+	n.walk(path.String(), fn)
+}
+
+func (n *radixNode) walk(key string, fn func(key string)) {
+	if n.leaf {
+		fn(key)
+	}
+	for _, c := range n.children {
+		c.walk(key+c.prefix, fn)
+	}
+}
+
+func commonPrefixLen(s1, s2 string) int {
+	l := 0
+	for l < len(s1) && l < len(s2) && s1[l] == s2[l] {
+		l++
+	}
+	return l
+}
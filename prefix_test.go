@@ -0,0 +1,105 @@
+package hashmap
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestPrefixIndex(t *testing.T) {
+	m := NewString[int]()
+	for i := 0; i < 100; i++ {
+		m.Put("user:"+strconv.Itoa(i), i)
+	}
+	pi := NewPrefixIndex(&m)
+	m.Put("user:420", 420)
+	m.Put("session:1", 1)
+	m.Put("user:1", -1)
+	m.Remove("user:42")
+
+	keys := pi.KeysWithPrefix("user:42")
+	if len(keys) != 1 || keys[0] != "user:420" {
+		t.Logf("keys: %v", keys)
+		t.FailNow()
+	}
+	keys = pi.KeysWithPrefix("user:1")
+	expected := []string{"user:1", "user:10", "user:11", "user:12", "user:13", "user:14", "user:15", "user:16", "user:17", "user:18", "user:19"}
+	if len(keys) != len(expected) {
+		t.Logf("keys: %v", keys)
+		t.FailNow()
+	}
+	for i := range expected {
+		if keys[i] != expected[i] {
+			t.Logf("keys: %v", keys)
+			t.FailNow()
+		}
+	}
+	if keys = pi.KeysWithPrefix(""); len(keys) != 101 {
+		t.Logf("keys: %d", len(keys))
+		t.FailNow()
+	}
+	if keys = pi.KeysWithPrefix("users"); len(keys) != 0 {
+		t.Logf("keys: %v", keys)
+		t.FailNow()
+	}
+	sum := 0
+	pi.RangePrefix("user:9", func(key string, val int) bool {
+		sum += val
+		return true
+	})
+	if sum != 9+90+91+92+93+94+95+96+97+98+99 {
+		t.Logf("sum: %d", sum)
+		t.FailNow()
+	}
+	if removed := pi.RemovePrefix("user:"); removed != 100 {
+		t.Logf("removed: %d", removed)
+		t.FailNow()
+	}
+	if keys = pi.KeysWithPrefix(""); len(keys) != 1 || keys[0] != "session:1" || m.Size() != 1 {
+		t.Logf("keys: %v", keys)
+		t.FailNow()
+	}
+	pi.Close()
+	m.Put("user:1", 1)
+	if keys = pi.KeysWithPrefix("user:"); len(keys) != 0 {
+		t.Logf("keys: %v", keys)
+		t.FailNow()
+	}
+}
+
+func TestPrefixIndex_ConcurrentlyPut_Remove(t *testing.T) {
+	m := NewString[int]()
+	pi := NewPrefixIndex(&m)
+	var wg sync.WaitGroup
+	putRemove := func(from, to int) {
+		defer wg.Done()
+		for i := from; i < to; i++ {
+			k := strconv.Itoa(i)
+			m.Put(k, i)
+			if i%2 == 0 {
+				m.Remove(k)
+			}
+		}
+	}
+	wg.Add(3)
+	go putRemove(0, 10_000)
+	go putRemove(5_000, 15_000)
+	go putRemove(10_000, 20_000)
+	wg.Wait()
+	keys := pi.KeysWithPrefix("")
+	if len(keys) != m.Size() || len(keys) != 10_000 {
+		t.Logf("keys: %d, size: %d", len(keys), m.Size())
+		t.FailNow()
+	}
+	for _, k := range keys {
+		if !m.Contains(k) {
+			t.Logf("key: %s", k)
+			t.FailNow()
+		}
+	}
+	m.Clear()
+	if keys = pi.KeysWithPrefix(""); len(keys) != 0 || len(pi.root.children) != 0 {
+		t.Logf("keys: %d", len(keys))
+		t.FailNow()
+	}
+}
@@ -0,0 +1,450 @@
+package hashmap
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	defaultReplicationRetain    = 1 << 16
+	defaultReplicationHeartbeat = time.Second
+	maxReplicationBatch         = 1024
+	maxReplicationBackoff       = 5 * time.Second
+)
+
+// ErrPrimaryClosed is returned by Primary.Serve after the primary is closed.
+var ErrPrimaryClosed = errors.New("primary closed")
+
+const (
+	opPut uint8 = iota + 1
+	opRemove
+	frameRecord
+	frameSnapshotStart
+	frameSnapshotEntry
+	frameSnapshotEnd
+	frameHeartbeat
+)
+
+// replHello is sent by a replica when it connects to the primary,
+// RunID is the run of the primary the offset belongs to.
+type replHello struct {
+	RunID  uint64
+	Offset uint64
+	Resume bool
+}
+
+// replFrame is sent by the primary to replicas. Head is the offset of the next record of the primary.
+type replFrame[k, v any] struct {
+	RunID  uint64
+	Type   uint8
+	Op     uint8
+	Offset uint64
+	Head   uint64
+	Time   int64
+	Key    k
+	Value  v
+}
+
+type logRecord[k, v any] struct {
+	op    uint8
+	time  int64
+	key   k
+	value v
+}
+
+// Primary records the changes of a map in a mutation log and streams it to replicas over net.Conn.
+// Keys and values are encoded by encoding/gob, so their types must be encodable by it.
+type Primary[k, v any] struct {
+	m *ConcurrentHashMap[k, v]
+	// runID identifies the log of this primary, offsets of another run of it are not resumable
+	runID     uint64
+	mu        sync.Mutex
+	log       []logRecord[k, v]
+	base      uint64
+	retain    int
+	heartbeat time.Duration
+	notify    chan struct{}
+	closed    bool
+	listeners map[net.Listener]struct{}
+	conns     map[net.Conn]struct{}
+}
+
+// NewPrimary returns Primary recording the changes of the given map,
+// retaining the last retain records for replicas to resume from.
+func NewPrimary[k, v any](m *ConcurrentHashMap[k, v], retain int) *Primary[k, v] {
+	if retain <= 0 {
+		retain = defaultReplicationRetain
+	}
+	p := &Primary[k, v]{
+		m:         m,
+		runID:     newRunID(),
+		retain:    retain,
+		heartbeat: defaultReplicationHeartbeat,
+		notify:    make(chan struct{}),
+		listeners: make(map[net.Listener]struct{}),
+		conns:     make(map[net.Conn]struct{}),
+	}
+	m.obs.add(p)
+	return p
+}
+
+// Offset returns the offset of the next record of the log.
+func (p *Primary[k, v]) Offset() uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.base + uint64(len(p.log))
+}
+
+// Close stops recording the changes of the map, closes the listeners and the connections to replicas.
+func (p *Primary[k, v]) Close() error {
+	p.m.obs.delete(p)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return nil
+	}
+	p.closed = true
+	close(p.notify)
+	var err error
+	for l := range p.listeners {
+		if lerr := l.Close(); lerr != nil && err == nil {
+			err = lerr
+		}
+	}
+	for c := range p.conns {
+		_ = c.Close()
+	}
+	return err
+}
+
+// Serve accepts replica connections on the listener and streams the log to each of them in its own goroutine.
+func (p *Primary[k, v]) Serve(l net.Listener) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return ErrPrimaryClosed
+	}
+	p.listeners[l] = struct{}{}
+	p.mu.Unlock()
+	for {
+		c, err := l.Accept()
+		if err != nil {
+			p.mu.Lock()
+			closed := p.closed
+			delete(p.listeners, l)
+			p.mu.Unlock()
+			if closed {
+				return ErrPrimaryClosed
+			}
+			return err
+		}
+		go func() {
+			_ = p.ServeConn(c)
+		}()
+	}
+}
+
+// ServeConn streams the log to the replica on the given connection, until the connection fails or the primary is closed.
+// A replica resuming from an offset which is still retained gets the records from that offset,
+// otherwise it gets a snapshot of the map first.
+func (p *Primary[k, v]) ServeConn(c net.Conn) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		_ = c.Close()
+		return ErrPrimaryClosed
+	}
+	p.conns[c] = struct{}{}
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		delete(p.conns, c)
+		p.mu.Unlock()
+		_ = c.Close()
+	}()
+	enc := gob.NewEncoder(c)
+	var hello replHello
+	if err := gob.NewDecoder(c).Decode(&hello); err != nil {
+		return err
+	}
+	offset := hello.Offset
+	p.mu.Lock()
+	resumable := hello.Resume && hello.RunID == p.runID && offset >= p.base && offset <= p.base+uint64(len(p.log))
+	p.mu.Unlock()
+	if !resumable {
+		var err error
+		if offset, err = p.sendSnapshot(enc); err != nil {
+			return err
+		}
+	}
+	ticker := time.NewTicker(p.heartbeat)
+	defer ticker.Stop()
+	for {
+		p.mu.Lock()
+		if p.closed {
+			p.mu.Unlock()
+			return ErrPrimaryClosed
+		}
+		if offset < p.base {
+			p.mu.Unlock()
+			return errors.New("replica fell behind the retained log")
+		}
+		head := p.base + uint64(len(p.log))
+		n := head - offset
+		if n > maxReplicationBatch {
+			n = maxReplicationBatch
+		}
+		batch := make([]logRecord[k, v], n)
+		copy(batch, p.log[offset-p.base:])
+		notify := p.notify
+		p.mu.Unlock()
+		for i, r := range batch {
+			f := replFrame[k, v]{RunID: p.runID, Type: frameRecord, Op: r.op, Offset: offset + uint64(i), Head: head, Time: r.time, Key: r.key, Value: r.value}
+			if err := enc.Encode(&f); err != nil {
+				return err
+			}
+		}
+		offset += n
+		if n > 0 {
+			continue
+		}
+		select {
+		case <-notify:
+		case <-ticker.C:
+			f := replFrame[k, v]{RunID: p.runID, Type: frameHeartbeat, Head: head, Time: time.Now().UnixNano()}
+			if err := enc.Encode(&f); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// sendSnapshot sends a snapshot of the map and returns the offset of the log the snapshot corresponds to.
+// Records from the offset may already be in the snapshot, applying them again is idempotent.
+func (p *Primary[k, v]) sendSnapshot(enc *gob.Encoder) (uint64, error) {
+	var offset uint64
+	s := p.m.snapshot(func() {
+		offset = p.Offset()
+	})
+	if err := enc.Encode(&replFrame[k, v]{RunID: p.runID, Type: frameSnapshotStart, Offset: offset, Time: time.Now().UnixNano()}); err != nil {
+		return 0, err
+	}
+	var err error
+	s.Range(func(key k, val v) bool {
+		err = enc.Encode(&replFrame[k, v]{RunID: p.runID, Type: frameSnapshotEntry, Key: key, Value: val})
+		return err == nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err = enc.Encode(&replFrame[k, v]{RunID: p.runID, Type: frameSnapshotEnd, Offset: offset}); err != nil {
+		return 0, err
+	}
+	return offset, nil
+}
+
+func (p *Primary[k, v]) onPut(h uint32, key k, val v, old v, replaced bool) {
+	p.append(logRecord[k, v]{op: opPut, key: key, value: val})
+}
+
+func (p *Primary[k, v]) onRemove(h uint32, key k, val v) {
+	p.append(logRecord[k, v]{op: opRemove, key: key})
+}
+
+func (p *Primary[k, v]) append(r logRecord[k, v]) {
+	r.time = time.Now().UnixNano()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return
+	}
+	if len(p.log) >= p.retain {
+		// drop the older half at once, so that trimming is amortized
+		drop := len(p.log) / 2
+		p.log = append(p.log[:0:0], p.log[drop:]...)
+		p.base += uint64(drop)
+	}
+	p.log = append(p.log, r)
+	close(p.notify)
+	p.notify = make(chan struct{})
+}
+
+// Replica applies the log streamed by a primary to a map.
+// It bootstraps from a snapshot of the primary, then tails the log, and resumes from its offset on reconnection.
+type Replica[k, v any] struct {
+	m         *ConcurrentHashMap[k, v]
+	mu        sync.Mutex
+	runID     uint64
+	offset    uint64
+	synced    bool
+	head      uint64
+	applied   int64
+	snapshots int
+}
+
+// ReplicationLag describes how far a replica is behind its primary.
+type ReplicationLag struct {
+	// Records is the count of records of the primary which are not applied yet.
+	Records uint64
+	// Delay is the time since the last applied record was written on the primary, zero if the replica is caught up.
+	Delay time.Duration
+}
+
+// NewReplica returns Replica applying the log of a primary to the given map.
+func NewReplica[k, v any](m *ConcurrentHashMap[k, v]) *Replica[k, v] {
+	return &Replica[k, v]{m: m}
+}
+
+// Offset returns the offset of the next record to apply.
+func (r *Replica[k, v]) Offset() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.offset
+}
+
+// Lag returns the replication lag as of the last frame received from the primary.
+func (r *Replica[k, v]) Lag() ReplicationLag {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.head <= r.offset {
+		return ReplicationLag{}
+	}
+	return ReplicationLag{
+		Records: r.head - r.offset,
+		Delay:   time.Since(time.Unix(0, r.applied)),
+	}
+}
+
+// Run connects to the primary by dial and applies its log, reconnecting with backoff when the connection fails,
+// until the context is done.
+func (r *Replica[k, v]) Run(ctx context.Context, dial func(ctx context.Context) (net.Conn, error)) error {
+	backoff := 10 * time.Millisecond
+	for {
+		c, err := dial(ctx)
+		if err == nil {
+			stop := make(chan struct{})
+			go func() {
+				select {
+				case <-ctx.Done():
+					_ = c.Close()
+				case <-stop:
+				}
+			}()
+			snapshots, offset := r.progress()
+			_ = r.Sync(c)
+			close(stop)
+			// a session which applied a snapshot or a record was healthy, so the next one is not delayed by the failed ones
+			if s, o := r.progress(); s != snapshots || o != offset {
+				backoff = 10 * time.Millisecond
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		if backoff < maxReplicationBackoff {
+			backoff *= 2
+		}
+	}
+}
+
+// progress returns the count of applied snapshots and the offset, one of them changes whenever the replica applies
+// a snapshot or a record.
+func (r *Replica[k, v]) progress() (int, uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.snapshots, r.offset
+}
+
+// Sync applies the log of the primary on the given connection until the connection fails.
+func (r *Replica[k, v]) Sync(c net.Conn) error {
+	defer c.Close()
+	r.mu.Lock()
+	hello := replHello{RunID: r.runID, Offset: r.offset, Resume: r.synced}
+	r.mu.Unlock()
+	if err := gob.NewEncoder(c).Encode(&hello); err != nil {
+		return err
+	}
+	dec := gob.NewDecoder(c)
+	var keys *ConcurrentHashSet[k]
+	var snapshotTime int64
+	for {
+		var f replFrame[k, v]
+		if err := dec.Decode(&f); err != nil {
+			return err
+		}
+		if f.Type != frameSnapshotStart && f.Type != frameSnapshotEntry && f.Type != frameSnapshotEnd {
+			r.mu.Lock()
+			runID := r.runID
+			r.mu.Unlock()
+			if f.RunID != runID {
+				return errors.New("record of another primary run")
+			}
+		}
+		switch f.Type {
+		case frameSnapshotStart:
+			s, _ := NewSetWithCapAndFuncs[k](int(r.m.capacity), r.m.hf, r.m.ef)
+			keys = &s
+			snapshotTime = f.Time
+		case frameSnapshotEntry:
+			if keys == nil {
+				return errors.New("snapshot entry out of snapshot")
+			}
+			r.m.Put(f.Key, f.Value)
+			keys.Add(f.Key)
+		case frameSnapshotEnd:
+			if keys == nil {
+				return errors.New("snapshot end out of snapshot")
+			}
+			r.m.RemoveIf(func(key k, val v) bool {
+				return !keys.Contains(key)
+			})
+			keys = nil
+			r.mu.Lock()
+			r.runID = f.RunID
+			r.offset = f.Offset
+			r.head = f.Offset
+			r.applied = snapshotTime
+			r.synced = true
+			r.snapshots++
+			r.mu.Unlock()
+		case frameRecord:
+			r.mu.Lock()
+			offset := r.offset
+			r.mu.Unlock()
+			if f.Offset != offset {
+				return errors.New("unexpected record offset")
+			}
+			if f.Op == opPut {
+				r.m.Put(f.Key, f.Value)
+			} else {
+				r.m.Remove(f.Key)
+			}
+			r.mu.Lock()
+			r.offset++
+			r.head = f.Head
+			r.applied = f.Time
+			r.mu.Unlock()
+		case frameHeartbeat:
+			r.mu.Lock()
+			r.head = f.Head
+			r.mu.Unlock()
+		}
+	}
+}
+
+// newRunID returns a random id for a run of a primary.
+func newRunID() uint64 {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return uint64(time.Now().UnixNano())
+	}
+	return binary.LittleEndian.Uint64(b[:])
+}
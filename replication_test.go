@@ -0,0 +1,318 @@
+package hashmap
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func startPrimary(t *testing.T, p *Primary[string, int]) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Logf("err: %v", err)
+		t.FailNow()
+	}
+	go func() {
+		_ = p.Serve(l)
+	}()
+	return l.Addr().String()
+}
+
+func dialer(addr string) func(ctx context.Context) (net.Conn, error) {
+	return func(ctx context.Context) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "tcp", addr)
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Log("condition is not met in time")
+			t.FailNow()
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func equalMaps(a, b *ConcurrentHashMap[string, int]) bool {
+	count := func(m *ConcurrentHashMap[string, int]) int {
+		n := 0
+		m.Range(func(key string, val int) bool {
+			n++
+			return true
+		})
+		return n
+	}
+	equal := true
+	a.Range(func(key string, val int) bool {
+		bv, ok := b.Get(key)
+		equal = ok && bv == val
+		return equal
+	})
+	return equal && count(a) == count(b)
+}
+
+func TestReplica_Sync(t *testing.T) {
+	pm := NewString[int]()
+	for i := 0; i < 1_000; i++ {
+		pm.Put(strconv.Itoa(i), i)
+	}
+	p := NewPrimary(&pm, 0)
+	defer p.Close()
+	p.heartbeat = 10 * time.Millisecond
+	addr := startPrimary(t, p)
+	rm := NewString[int]()
+	rm.Put("stale", -1)
+	r := NewReplica(&rm)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = r.Run(ctx, dialer(addr))
+	}()
+	waitFor(t, func() bool {
+		return equalMaps(&pm, &rm)
+	})
+	for i := 0; i < 500; i++ {
+		pm.Remove(strconv.Itoa(i))
+	}
+	for i := 1_000; i < 1_500; i++ {
+		pm.Put(strconv.Itoa(i), i)
+	}
+	pm.Put("1000", -1000)
+	waitFor(t, func() bool {
+		return r.Offset() == p.Offset() && equalMaps(&pm, &rm)
+	})
+	if lag := r.Lag(); lag.Records != 0 || lag.Delay != 0 {
+		t.Logf("lag: %+v", lag)
+		t.FailNow()
+	}
+}
+
+func TestReplica_Sync_ConcurrentWrites(t *testing.T) {
+	pm := NewString[int]()
+	p := NewPrimary(&pm, 0)
+	defer p.Close()
+	addr := startPrimary(t, p)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 5_000; i++ {
+			key := strconv.Itoa(i % 300)
+			if i%7 == 0 {
+				pm.Remove(key)
+			} else {
+				pm.Put(key, i)
+			}
+		}
+	}()
+	rm := NewString[int]()
+	r := NewReplica(&rm)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = r.Run(ctx, dialer(addr))
+	}()
+	<-done
+	waitFor(t, func() bool {
+		return r.Offset() == p.Offset() && equalMaps(&pm, &rm)
+	})
+}
+
+func TestReplica_Sync_Resume(t *testing.T) {
+	pm := NewString[int]()
+	p := NewPrimary(&pm, 0)
+	defer p.Close()
+	addr := startPrimary(t, p)
+	rm := NewString[int]()
+	r := NewReplica(&rm)
+	pm.Put("a", 1)
+	c, _ := net.Dial("tcp", addr)
+	go func() {
+		_ = r.Sync(c)
+	}()
+	waitFor(t, func() bool {
+		return r.Offset() == p.Offset() && equalMaps(&pm, &rm)
+	})
+	_ = c.Close()
+	pm.Put("b", 2)
+	pm.Remove("a")
+	c, _ = net.Dial("tcp", addr)
+	defer c.Close()
+	go func() {
+		_ = r.Sync(c)
+	}()
+	waitFor(t, func() bool {
+		return r.Offset() == p.Offset() && equalMaps(&pm, &rm)
+	})
+	r.mu.Lock()
+	snapshots := r.snapshots
+	r.mu.Unlock()
+	if snapshots != 1 {
+		t.Logf("snapshots: %d", snapshots)
+		t.FailNow()
+	}
+}
+
+func TestReplica_Sync_ResumeBehindLog(t *testing.T) {
+	pm := NewString[int]()
+	p := NewPrimary(&pm, 8)
+	defer p.Close()
+	addr := startPrimary(t, p)
+	rm := NewString[int]()
+	r := NewReplica(&rm)
+	c, _ := net.Dial("tcp", addr)
+	go func() {
+		_ = r.Sync(c)
+	}()
+	waitFor(t, func() bool {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return r.synced
+	})
+	_ = c.Close()
+	for i := 0; i < 100; i++ {
+		pm.Put(strconv.Itoa(i), i)
+	}
+	c, _ = net.Dial("tcp", addr)
+	defer c.Close()
+	go func() {
+		_ = r.Sync(c)
+	}()
+	waitFor(t, func() bool {
+		return r.Offset() == p.Offset() && equalMaps(&pm, &rm)
+	})
+	r.mu.Lock()
+	snapshots := r.snapshots
+	r.mu.Unlock()
+	if snapshots != 2 {
+		t.Logf("snapshots: %d", snapshots)
+		t.FailNow()
+	}
+}
+
+func TestReplica_Lag(t *testing.T) {
+	pm := NewString[int]()
+	p := NewPrimary(&pm, 0)
+	defer p.Close()
+	rm := NewString[int]()
+	r := NewReplica(&rm)
+	pc, rc := net.Pipe()
+	go func() {
+		_ = p.ServeConn(pc)
+	}()
+	go func() {
+		_ = r.Sync(rc)
+	}()
+	waitFor(t, func() bool {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return r.synced
+	})
+	r.mu.Lock()
+	r.head = r.offset + 3
+	r.mu.Unlock()
+	if lag := r.Lag(); lag.Records != 3 || lag.Delay <= 0 {
+		t.Logf("lag: %+v", lag)
+		t.FailNow()
+	}
+	_ = p.Close()
+}
+
+func TestReplica_Sync_PrimaryRestart(t *testing.T) {
+	pm := NewString[int]()
+	p := NewPrimary(&pm, 0)
+	addr := startPrimary(t, p)
+	rm := NewString[int]()
+	r := NewReplica(&rm)
+	for i := 0; i < 10; i++ {
+		pm.Put(strconv.Itoa(i), i)
+	}
+	c, _ := net.Dial("tcp", addr)
+	go func() {
+		_ = r.Sync(c)
+	}()
+	waitFor(t, func() bool {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return r.synced
+	})
+	for i := 10; i < 20; i++ {
+		pm.Put(strconv.Itoa(i), i)
+	}
+	waitFor(t, func() bool {
+		return r.Offset() == p.Offset() && equalMaps(&pm, &rm)
+	})
+	_ = p.Close()
+	restarted := NewString[int]()
+	p = NewPrimary(&restarted, 0)
+	defer p.Close()
+	addr = startPrimary(t, p)
+	for i := 0; i < 30; i++ {
+		restarted.Put("restarted"+strconv.Itoa(i), i)
+	}
+	c, _ = net.Dial("tcp", addr)
+	defer c.Close()
+	go func() {
+		_ = r.Sync(c)
+	}()
+	waitFor(t, func() bool {
+		return r.Offset() == p.Offset() && equalMaps(&restarted, &rm)
+	})
+	r.mu.Lock()
+	snapshots := r.snapshots
+	r.mu.Unlock()
+	if snapshots != 2 {
+		t.Logf("snapshots: %d", snapshots)
+		t.FailNow()
+	}
+}
+
+func TestReplica_Run_Reconnect(t *testing.T) {
+	pm := NewString[int]()
+	p := NewPrimary(&pm, 0)
+	defer p.Close()
+	addr := startPrimary(t, p)
+	rm := NewString[int]()
+	r := NewReplica(&rm)
+	conns := make(chan net.Conn, 1)
+	dial := func(ctx context.Context) (net.Conn, error) {
+		c, err := dialer(addr)(ctx)
+		if err == nil {
+			select {
+			case conns <- c:
+			case <-ctx.Done():
+			}
+		}
+		return c, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = r.Run(ctx, dial)
+	}()
+	c := <-conns
+	for i := 0; i < 10; i++ {
+		pm.Put(strconv.Itoa(i), i)
+		waitFor(t, func() bool {
+			return r.Offset() == p.Offset()
+		})
+		_ = c.Close()
+		dropped := time.Now()
+		select {
+		case c = <-conns:
+		case <-time.After(5 * time.Second):
+			t.Log("replica does not reconnect")
+			t.FailNow()
+		}
+		// sessions which made progress must not grow the backoff
+		if d := time.Since(dropped); d > 200*time.Millisecond {
+			t.Logf("round: %d, reconnect: %v", i, d)
+			t.FailNow()
+		}
+	}
+	_ = c.Close()
+}
@@ -0,0 +1,86 @@
+package server
+
+// globMatch reports whether s matches the glob pattern with the semantics of redis:
+// '*' matches any sequence of bytes, '?' matches any byte, '[...]' matches a byte in the set,
+// which can be negated by '^' and contain ranges like 'a-z', and '\' escapes the next byte.
+// Unlike path.Match, '/' is not special and every pattern is valid, an unterminated set ends at the end of the pattern.
+func globMatch(pattern, s string) bool {
+	px, sx := 0, 0
+	// position of the last star and of the byte it is tried to match up to, to backtrack on mismatch
+	starP, starS := -1, 0
+	for sx < len(s) {
+		if px < len(pattern) {
+			switch c := pattern[px]; c {
+			case '*':
+				starP, starS = px, sx
+				px++
+				continue
+			case '?':
+				px++
+				sx++
+				continue
+			case '[':
+				if ok, next := matchSet(pattern, px+1, s[sx]); ok {
+					px = next
+					sx++
+					continue
+				}
+			case '\\':
+				if px+1 < len(pattern) {
+					c = pattern[px+1]
+					px++
+				}
+				if c == s[sx] {
+					px++
+					sx++
+					continue
+				}
+			default:
+				if c == s[sx] {
+					px++
+					sx++
+					continue
+				}
+			}
+		}
+		if starP < 0 {
+			return false
+		}
+		starS++
+		px, sx = starP+1, starS
+	}
+	for px < len(pattern) && pattern[px] == '*' {
+		px++
+	}
+	return px == len(pattern)
+}
+
+// matchSet matches c against the set starting at the index i of the pattern, just after '[',
+// and returns whether it matches and the index after the set.
+func matchSet(pattern string, i int, c byte) (bool, int) {
+	negate := i < len(pattern) && pattern[i] == '^'
+	if negate {
+		i++
+	}
+	matched := false
+	for ; i < len(pattern) && pattern[i] != ']'; i++ {
+		switch {
+		case pattern[i] == '\\' && i+1 < len(pattern):
+			i++
+			matched = matched || pattern[i] == c
+		case i+2 < len(pattern) && pattern[i+1] == '-':
+			lo, hi := pattern[i], pattern[i+2]
+			if lo > hi {
+				lo, hi = hi, lo
+			}
+			matched = matched || lo <= c && c <= hi
+			i += 2
+		default:
+			matched = matched || pattern[i] == c
+		}
+	}
+	if i < len(pattern) {
+		i++
+	}
+	return matched != negate, i
+}
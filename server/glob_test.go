@@ -0,0 +1,48 @@
+package server
+
+import "testing"
+
+func TestGlobMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		s       string
+		match   bool
+	}{
+		{"", "", true},
+		{"", "a", false},
+		{"*", "", true},
+		{"*", "user:1/profile", true},
+		{"user:*", "user:1/profile", true},
+		{"user:*", "users", false},
+		{"user:?/profile", "user:1/profile", true},
+		{"a?c", "a/c", true},
+		{"a?c", "ac", false},
+		{"*b*d", "abcbd", true},
+		{"*b*d", "abcbe", false},
+		{"a**b", "ab", true},
+		{"h[ae]llo", "hello", true},
+		{"h[ae]llo", "hillo", false},
+		{"h[^e]llo", "hallo", true},
+		{"h[^e]llo", "hello", false},
+		{"h[a-b]llo", "hbllo", true},
+		{"h[b-a]llo", "hallo", true},
+		{"h[a-b]llo", "hcllo", false},
+		{"[\\]]", "]", true},
+		{"[]a", "a", false},
+		{"[a-", "-", true},
+		{"[a-", "a", true},
+		{"[abc", "b", true},
+		{"\\*", "*", true},
+		{"\\*", "a", false},
+		{"a\\", "a\\", true},
+		{"[", "a", false},
+		{"ab[", "ab", false},
+		{"\xff*", "\xff\x00", true},
+	}
+	for _, test := range tests {
+		if match := globMatch(test.pattern, test.s); match != test.match {
+			t.Logf("pattern: %q, s: %q, match: %v", test.pattern, test.s, match)
+			t.FailNow()
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/semihbkgr/hashmap"
+)
+
+const (
+	defaultListCount = 100
+	maxBodySize      = 32 << 20
+	contentTypeJSON  = "application/json"
+)
+
+// KeyCodec parses keys from URL path segments and formats them back.
+type KeyCodec[k any] interface {
+	ParseKey(s string) (k, error)
+	FormatKey(key k) string
+}
+
+// ValueCodec encodes values to request and response bodies and decodes them back.
+type ValueCodec[v any] interface {
+	Encode(val v) ([]byte, error)
+	Decode(b []byte) (v, error)
+	ContentType() string
+}
+
+// StringKeyCodec is KeyCodec of string keys.
+type StringKeyCodec struct{}
+
+// ParseKey returns the string as is.
+func (StringKeyCodec) ParseKey(s string) (string, error) {
+	return s, nil
+}
+
+// FormatKey returns the key as is.
+func (StringKeyCodec) FormatKey(key string) string {
+	return key
+}
+
+// IntKeyCodec is KeyCodec of int keys.
+type IntKeyCodec struct{}
+
+// ParseKey parses the string as a decimal integer.
+func (IntKeyCodec) ParseKey(s string) (int, error) {
+	return strconv.Atoi(s)
+}
+
+// FormatKey formats the key as a decimal integer.
+func (IntKeyCodec) FormatKey(key int) string {
+	return strconv.Itoa(key)
+}
+
+// JSONCodec is ValueCodec encoding values as JSON.
+type JSONCodec[v any] struct{}
+
+// Encode returns the JSON encoding of the value.
+func (JSONCodec[v]) Encode(val v) ([]byte, error) {
+	return json.Marshal(val)
+}
+
+// Decode parses the JSON encoded value.
+func (JSONCodec[v]) Decode(b []byte) (val v, err error) {
+	err = json.Unmarshal(b, &val)
+	return
+}
+
+// ContentType returns the JSON content type.
+func (JSONCodec[v]) ContentType() string {
+	return contentTypeJSON
+}
+
+// BytesCodec is ValueCodec of raw byte slice values.
+type BytesCodec struct{}
+
+// Encode returns the value as is.
+func (BytesCodec) Encode(val []byte) ([]byte, error) {
+	return val, nil
+}
+
+// Decode returns a copy of the body.
+func (BytesCodec) Decode(b []byte) ([]byte, error) {
+	return append([]byte(nil), b...), nil
+}
+
+// ContentType returns the binary content type.
+func (BytesCodec) ContentType() string {
+	return "application/octet-stream"
+}
+
+// HTTPHandler exposes a ConcurrentHashMap over HTTP, to inspect and manipulate a map of a live service.
+//
+//	GET    /keys?cursor=0&count=100  lists keys page by page, the response contains the cursor of the next page
+//	GET    /keys/{key}               returns the value of the key
+//	PUT    /keys/{key}               puts the value in the request body
+//	DELETE /keys/{key}               removes the key
+//	GET    /stats                    returns the stats of the map, with the hot keys if they are tracked
+//	GET    /snapshot                 downloads a point-in-time snapshot of all entries as JSON
+//
+// The handler serves paths at its root, use http.StripPrefix to mount it under another path.
+type HTTPHandler[k, v any] struct {
+	m  *hashmap.ConcurrentHashMap[k, v]
+	kc KeyCodec[k]
+	vc ValueCodec[v]
+}
+
+// NewHTTPHandler returns HTTPHandler of the given map using the given codecs.
+func NewHTTPHandler[k, v any](m *hashmap.ConcurrentHashMap[k, v], kc KeyCodec[k], vc ValueCodec[v]) (*HTTPHandler[k, v], error) {
+	if m == nil || kc == nil || vc == nil {
+		return nil, errors.New("map and codecs cannot be nil")
+	}
+	return &HTTPHandler[k, v]{m: m, kc: kc, vc: vc}, nil
+}
+
+type listResponse struct {
+	Keys   []string `json:"keys"`
+	Cursor int      `json:"cursor"`
+}
+
+type statsResponse struct {
+	hashmap.Stats
+	HotKeys []hotKey `json:"hot_keys,omitempty"`
+}
+
+type hotKey struct {
+	Key  string  `json:"key"`
+	Rate float64 `json:"rate"`
+}
+
+type snapshotEntry struct {
+	Key   string `json:"key"`
+	Value any    `json:"value"`
+}
+
+// ServeHTTP serves the map API.
+func (h *HTTPHandler[k, v]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p := r.URL.EscapedPath()
+	switch {
+	case p == "/keys":
+		h.allow(w, r, http.MethodGet, h.list)
+	case strings.HasPrefix(p, "/keys/"):
+		s, err := url.PathUnescape(strings.TrimPrefix(p, "/keys/"))
+		if err != nil {
+			http.Error(w, "invalid key", http.StatusBadRequest)
+			return
+		}
+		key, err := h.kc.ParseKey(s)
+		if err != nil {
+			http.Error(w, "invalid key: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+			h.get(w, key)
+		case http.MethodPut:
+			h.put(w, r, key)
+		case http.MethodDelete:
+			h.delete(w, key)
+		default:
+			w.Header().Set("Allow", "GET, PUT, DELETE")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		}
+	case p == "/stats":
+		h.allow(w, r, http.MethodGet, h.stats)
+	case p == "/snapshot":
+		h.allow(w, r, http.MethodGet, h.snapshot)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *HTTPHandler[k, v]) allow(w http.ResponseWriter, r *http.Request, method string, fn func(w http.ResponseWriter, r *http.Request)) {
+	if r.Method != method && !(method == http.MethodGet && r.Method == http.MethodHead) {
+		w.Header().Set("Allow", method)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	fn(w, r)
+}
+
+func (h *HTTPHandler[k, v]) list(w http.ResponseWriter, r *http.Request) {
+	cursor, count := 0, defaultListCount
+	var err error
+	q := r.URL.Query()
+	if s := q.Get("cursor"); s != "" {
+		if cursor, err = strconv.Atoi(s); err != nil || cursor < 0 {
+			http.Error(w, "invalid cursor", http.StatusBadRequest)
+			return
+		}
+	}
+	if s := q.Get("count"); s != "" {
+		if count, err = strconv.Atoi(s); err != nil || count <= 0 {
+			http.Error(w, "invalid count", http.StatusBadRequest)
+			return
+		}
+	}
+	entries, next := h.m.Scan(cursor, count)
+	resp := listResponse{Keys: make([]string, len(entries)), Cursor: next}
+	for i, e := range entries {
+		resp.Keys[i] = h.kc.FormatKey(e.Key)
+	}
+	writeJSON(w, resp)
+}
+
+func (h *HTTPHandler[k, v]) get(w http.ResponseWriter, key k) {
+	val, ok := h.m.Get(key)
+	if !ok {
+		http.Error(w, "key not found", http.StatusNotFound)
+		return
+	}
+	b, err := h.vc.Encode(val)
+	if err != nil {
+		http.Error(w, "cannot encode value: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", h.vc.ContentType())
+	_, _ = w.Write(b)
+}
+
+func (h *HTTPHandler[k, v]) put(w http.ResponseWriter, r *http.Request, key k) {
+	b, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		// the reader fails only after returning the whole limit when the body is too large
+		if len(b) >= maxBodySize {
+			http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "cannot read body", http.StatusBadRequest)
+		return
+	}
+	val, err := h.vc.Decode(b)
+	if err != nil {
+		http.Error(w, "invalid value: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	// putting the value clears the expiration time set for the key through the other servers
+	h.m.Put(key, val)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *HTTPHandler[k, v]) delete(w http.ResponseWriter, key k) {
+	if _, ok := h.m.Remove(key); !ok {
+		http.Error(w, "key not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *HTTPHandler[k, v]) stats(w http.ResponseWriter, r *http.Request) {
+	resp := statsResponse{Stats: h.m.Stats()}
+	for _, hk := range h.m.HotKeys() {
+		resp.HotKeys = append(resp.HotKeys, hotKey{Key: h.kc.FormatKey(hk.Key), Rate: hk.Rate})
+	}
+	writeJSON(w, resp)
+}
+
+// snapshot writes the entries of a snapshot as a JSON array, values are embedded as JSON
+// if the value codec encodes JSON, or as base64 strings otherwise.
+func (h *HTTPHandler[k, v]) snapshot(w http.ResponseWriter, r *http.Request) {
+	s := h.m.Snapshot()
+	entries := make([]snapshotEntry, 0, s.Size())
+	var err error
+	s.Range(func(key k, val v) bool {
+		var b []byte
+		if b, err = h.vc.Encode(val); err != nil {
+			return false
+		}
+		e := snapshotEntry{Key: h.kc.FormatKey(key), Value: b}
+		if h.vc.ContentType() == contentTypeJSON {
+			e.Value = json.RawMessage(b)
+		}
+		entries = append(entries, e)
+		return true
+	})
+	if err != nil {
+		http.Error(w, "cannot encode value: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Disposition", `attachment; filename="snapshot.json"`)
+	writeJSON(w, entries)
+}
+
+func writeJSON(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", contentTypeJSON)
+	_ = json.NewEncoder(w).Encode(body)
+}
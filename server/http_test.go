@@ -0,0 +1,218 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/semihbkgr/hashmap"
+)
+
+type user struct {
+	Name string `json:"name"`
+}
+
+func newTestHTTPServer(t *testing.T) (*hashmap.ConcurrentHashMap[string, user], *httptest.Server) {
+	m := hashmap.NewString[user]()
+	h, err := NewHTTPHandler[string, user](&m, StringKeyCodec{}, JSONCodec[user]{})
+	if err != nil {
+		t.FailNow()
+	}
+	s := httptest.NewServer(h)
+	t.Cleanup(s.Close)
+	return &m, s
+}
+
+func doRequest(t *testing.T, method, url, body string) (int, string) {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	b, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(b)
+}
+
+func TestHTTPHandler_Keys(t *testing.T) {
+	m, s := newTestHTTPServer(t)
+	if code, _ := doRequest(t, http.MethodPut, s.URL+"/keys/user%2F1", `{"name":"alice"}`); code != http.StatusNoContent {
+		t.Logf("code: %d", code)
+		t.FailNow()
+	}
+	if u, ok := m.Get("user/1"); !ok || u.Name != "alice" {
+		t.Logf("user: %v, ok: %v", u, ok)
+		t.FailNow()
+	}
+	if code, body := doRequest(t, http.MethodGet, s.URL+"/keys/user%2F1", ""); code != http.StatusOK || strings.TrimSpace(body) != `{"name":"alice"}` {
+		t.Logf("code: %d, body: %s", code, body)
+		t.FailNow()
+	}
+	if code, _ := doRequest(t, http.MethodPut, s.URL+"/keys/user%2F2", `{"name":`); code != http.StatusBadRequest {
+		t.Logf("code: %d", code)
+		t.FailNow()
+	}
+	if code, _ := doRequest(t, http.MethodDelete, s.URL+"/keys/user%2F1", ""); code != http.StatusNoContent {
+		t.Logf("code: %d", code)
+		t.FailNow()
+	}
+	if code, _ := doRequest(t, http.MethodGet, s.URL+"/keys/user%2F1", ""); code != http.StatusNotFound {
+		t.Logf("code: %d", code)
+		t.FailNow()
+	}
+	if code, _ := doRequest(t, http.MethodPost, s.URL+"/keys/user%2F1", ""); code != http.StatusMethodNotAllowed {
+		t.Logf("code: %d", code)
+		t.FailNow()
+	}
+	if code, _ := doRequest(t, http.MethodGet, s.URL+"/unknown", ""); code != http.StatusNotFound {
+		t.Logf("code: %d", code)
+		t.FailNow()
+	}
+}
+
+func TestHTTPHandler_List(t *testing.T) {
+	m, s := newTestHTTPServer(t)
+	for i := 0; i < 250; i++ {
+		m.Put(strconv.Itoa(i), user{Name: strconv.Itoa(i)})
+	}
+	seen := make(map[string]bool)
+	cursor := 0
+	for {
+		code, body := doRequest(t, http.MethodGet, s.URL+"/keys?count=50&cursor="+strconv.Itoa(cursor), "")
+		var resp listResponse
+		if code != http.StatusOK || json.Unmarshal([]byte(body), &resp) != nil {
+			t.Logf("code: %d, body: %s", code, body)
+			t.FailNow()
+		}
+		for _, key := range resp.Keys {
+			seen[key] = true
+		}
+		if cursor = resp.Cursor; cursor == 0 {
+			break
+		}
+	}
+	if len(seen) != 250 {
+		t.Logf("seen: %d", len(seen))
+		t.FailNow()
+	}
+	if code, _ := doRequest(t, http.MethodGet, s.URL+"/keys?cursor=x", ""); code != http.StatusBadRequest {
+		t.Logf("code: %d", code)
+		t.FailNow()
+	}
+}
+
+func TestHTTPHandler_Stats_Snapshot(t *testing.T) {
+	m, s := newTestHTTPServer(t)
+	for i := 0; i < 100; i++ {
+		m.Put(strconv.Itoa(i), user{Name: "user" + strconv.Itoa(i)})
+	}
+	code, body := doRequest(t, http.MethodGet, s.URL+"/stats", "")
+	var stats hashmap.Stats
+	if code != http.StatusOK || json.Unmarshal([]byte(body), &stats) != nil || stats.Size != 100 {
+		t.Logf("code: %d, body: %s", code, body)
+		t.FailNow()
+	}
+	_ = m.EnableHotKeys(2, time.Minute, 1)
+	for i := 0; i < 10; i++ {
+		m.Get("42")
+	}
+	code, body = doRequest(t, http.MethodGet, s.URL+"/stats", "")
+	var hot struct {
+		HotKeys []struct {
+			Key string `json:"key"`
+		} `json:"hot_keys"`
+		HotBuckets []hashmap.HotBucket `json:"hot_buckets"`
+	}
+	if code != http.StatusOK || json.Unmarshal([]byte(body), &hot) != nil || len(hot.HotKeys) == 0 || hot.HotKeys[0].Key != "42" || len(hot.HotBuckets) == 0 {
+		t.Logf("code: %d, body: %s", code, body)
+		t.FailNow()
+	}
+	code, body = doRequest(t, http.MethodGet, s.URL+"/snapshot", "")
+	var entries []struct {
+		Key   string `json:"key"`
+		Value user   `json:"value"`
+	}
+	if code != http.StatusOK || json.Unmarshal([]byte(body), &entries) != nil || len(entries) != 100 {
+		t.Logf("code: %d, body: %s", code, body)
+		t.FailNow()
+	}
+	for _, e := range entries {
+		if e.Value.Name != "user"+e.Key {
+			t.Logf("entry: %v", e)
+			t.FailNow()
+		}
+	}
+}
+
+func TestHTTPHandler_Bytes(t *testing.T) {
+	m := hashmap.NewString[[]byte]()
+	h, _ := NewHTTPHandler[string, []byte](&m, StringKeyCodec{}, BytesCodec{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+	m.Put("a", []byte("hello"))
+	if code, body := doRequest(t, http.MethodGet, s.URL+"/keys/a", ""); code != http.StatusOK || body != "hello" {
+		t.Logf("code: %d, body: %s", code, body)
+		t.FailNow()
+	}
+	if code, body := doRequest(t, http.MethodGet, s.URL+"/snapshot", ""); code != http.StatusOK || !strings.Contains(body, `"value":"aGVsbG8="`) {
+		t.Logf("code: %d, body: %s", code, body)
+		t.FailNow()
+	}
+	if _, err := NewHTTPHandler[string, []byte](&m, nil, BytesCodec{}); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestHTTPHandler_Put_TooLarge(t *testing.T) {
+	m := hashmap.NewString[[]byte]()
+	h, _ := NewHTTPHandler[string, []byte](&m, StringKeyCodec{}, BytesCodec{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+	body := strings.Repeat("a", maxBodySize+1)
+	if code, _ := doRequest(t, http.MethodPut, s.URL+"/keys/a", body); code != http.StatusRequestEntityTooLarge {
+		t.Logf("code: %d", code)
+		t.FailNow()
+	}
+	if m.Contains("a") {
+		t.Log("truncated value is saved")
+		t.FailNow()
+	}
+	if code, _ := doRequest(t, http.MethodPut, s.URL+"/keys/a", body[1:]); code != http.StatusNoContent {
+		t.Logf("code: %d", code)
+		t.FailNow()
+	}
+	if v, _ := m.Get("a"); len(v) != maxBodySize {
+		t.Logf("len: %d", len(v))
+		t.FailNow()
+	}
+}
+
+func TestHTTPHandler_Put_Expire(t *testing.T) {
+	m := hashmap.NewString[[]byte]()
+	h, _ := NewHTTPHandler[string, []byte](&m, StringKeyCodec{}, BytesCodec{})
+	s := httptest.NewServer(h)
+	defer s.Close()
+	rc := dialRESP(t, serveLoopback(t, NewRESPServer(&m)))
+	rc.do(t, "SET", "a", "old", "PX", "100")
+	if code, _ := doRequest(t, http.MethodPut, s.URL+"/keys/a", "new"); code != http.StatusNoContent {
+		t.Logf("code: %d", code)
+		t.FailNow()
+	}
+	if r := rc.do(t, "TTL", "a"); r != int64(-1) {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	time.Sleep(expireInterval + 200*time.Millisecond)
+	if code, body := doRequest(t, http.MethodGet, s.URL+"/keys/a", ""); code != http.StatusOK || body != "new" {
+		t.Logf("code: %d, body: %s", code, body)
+		t.FailNow()
+	}
+}
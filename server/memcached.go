@@ -0,0 +1,334 @@
+package server
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/semihbkgr/hashmap"
+)
+
+const (
+	maxMemcachedKeyLen = 250
+	// exptime values up to 30 days are relative, greater values are unix timestamps
+	maxRelativeExptime = 60 * 60 * 24 * 30
+)
+
+// MemcachedServer serves a string type key ConcurrentHashMap over the memcached ASCII protocol.
+// Supported commands are get, gets, set, add, replace, cas, delete, incr, decr, version and quit.
+// Flags and cas values are kept alongside the map and updated atomically with the values, under the bucket locks of the map.
+// Expiration times are kept by the map itself, so writes through the other servers of the map reset them.
+type MemcachedServer struct {
+	m     *hashmap.ConcurrentHashMap[string, []byte]
+	meta  hashmap.ConcurrentHashMap[string, itemMeta]
+	cas   *uint64
+	conns *conns
+}
+
+type itemMeta struct {
+	flags uint32
+	cas   uint64
+}
+
+// NewMemcachedServer returns MemcachedServer serving the given map.
+func NewMemcachedServer(m *hashmap.ConcurrentHashMap[string, []byte]) *MemcachedServer {
+	return &MemcachedServer{
+		m:     m,
+		meta:  hashmap.NewString[itemMeta](),
+		cas:   new(uint64),
+		conns: newConns(),
+	}
+}
+
+// ListenAndServe listens on the given TCP address and serves connections.
+func (s *MemcachedServer) ListenAndServe(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(l)
+}
+
+// Serve accepts connections on the listener and serves each of them in its own goroutine.
+// It returns when the listener fails or the server is closed.
+func (s *MemcachedServer) Serve(l net.Listener) error {
+	return s.conns.serve(l, s.serveConn)
+}
+
+// Close closes the listeners and connections of the server.
+func (s *MemcachedServer) Close() error {
+	return s.conns.close()
+}
+
+func (s *MemcachedServer) serveConn(c net.Conn) {
+	r := bufio.NewReader(c)
+	w := bufio.NewWriter(c)
+	for {
+		line, err := readLine(r)
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			_, _ = w.WriteString("ERROR\r\n")
+		} else if quit := s.exec(r, w, fields); quit {
+			_ = w.Flush()
+			return
+		}
+		if r.Buffered() == 0 {
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *MemcachedServer) exec(r *bufio.Reader, w *bufio.Writer, fields []string) bool {
+	switch cmd, args := fields[0], fields[1:]; cmd {
+	case "get", "gets":
+		if len(args) == 0 {
+			_, _ = w.WriteString("ERROR\r\n")
+			break
+		}
+		s.get(w, args, cmd == "gets")
+	case "set", "add", "replace", "cas":
+		return s.store(r, w, cmd, args)
+	case "delete":
+		if len(args) < 1 || len(args) > 2 {
+			_, _ = w.WriteString("ERROR\r\n")
+			break
+		}
+		reply := "NOT_FOUND"
+		if s.delete(args[0]) {
+			reply = "DELETED"
+		}
+		writeReply(w, reply, noreply(args, 1))
+	case "incr", "decr":
+		if len(args) < 2 || len(args) > 3 {
+			_, _ = w.WriteString("ERROR\r\n")
+			break
+		}
+		delta, err := strconv.ParseUint(args[1], 10, 64)
+		if err != nil {
+			writeReply(w, "CLIENT_ERROR invalid numeric delta argument", false)
+			break
+		}
+		writeReply(w, s.incr(args[0], delta, cmd == "incr"), noreply(args, 2))
+	case "version":
+		_, _ = w.WriteString("VERSION hashmap\r\n")
+	case "quit":
+		return true
+	default:
+		_, _ = w.WriteString("ERROR\r\n")
+	}
+	return false
+}
+
+func (s *MemcachedServer) get(w *bufio.Writer, keys []string, withCas bool) {
+	type item struct {
+		val  []byte
+		meta itemMeta
+		ok   bool
+	}
+	items := make([]item, len(keys))
+	_ = s.m.Tx(keys, func(tx hashmap.TxView[string, []byte]) error {
+		for i, key := range keys {
+			items[i].val, items[i].meta, items[i].ok = s.load(tx, key)
+		}
+		return nil
+	})
+	for i, key := range keys {
+		it := items[i]
+		if !it.ok {
+			continue
+		}
+		_, _ = w.WriteString("VALUE " + key + " " + strconv.FormatUint(uint64(it.meta.flags), 10) + " " + strconv.Itoa(len(it.val)))
+		if withCas {
+			_, _ = w.WriteString(" " + strconv.FormatUint(it.meta.cas, 10))
+		}
+		_, _ = w.WriteString("\r\n")
+		_, _ = w.Write(it.val)
+		_, _ = w.WriteString("\r\n")
+	}
+	_, _ = w.WriteString("END\r\n")
+}
+
+// store handles storage commands, it returns true if the connection should be closed.
+func (s *MemcachedServer) store(r *bufio.Reader, w *bufio.Writer, cmd string, args []string) bool {
+	n := 4
+	if cmd == "cas" {
+		n = 5
+	}
+	if len(args) < n || len(args) > n+1 {
+		_, _ = w.WriteString("ERROR\r\n")
+		return false
+	}
+	key := args[0]
+	flags, ferr := strconv.ParseUint(args[1], 10, 32)
+	exptime, eerr := strconv.ParseInt(args[2], 10, 64)
+	size, serr := strconv.Atoi(args[3])
+	var casUnique uint64
+	var cerr error
+	if cmd == "cas" {
+		casUnique, cerr = strconv.ParseUint(args[4], 10, 64)
+	}
+	if serr != nil || size < 0 || size > maxBulkLen {
+		// the data block cannot be skipped without its size
+		writeReply(w, "CLIENT_ERROR bad command line format", false)
+		return true
+	}
+	data := make([]byte, size+2)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return true
+	}
+	if data[size] != '\r' || data[size+1] != '\n' {
+		writeReply(w, "CLIENT_ERROR bad data chunk", false)
+		return false
+	}
+	if ferr != nil || eerr != nil || cerr != nil || !validKey(key) {
+		writeReply(w, "CLIENT_ERROR bad command line format", false)
+		return false
+	}
+	val := data[:size:size]
+	meta := itemMeta{flags: uint32(flags)}
+	at := expireAt(exptime)
+	reply := "NOT_STORED"
+	_ = s.m.Tx([]string{key}, func(tx hashmap.TxView[string, []byte]) error {
+		_, cur, exists := s.load(tx, key)
+		switch cmd {
+		case "add":
+			if exists {
+				return nil
+			}
+		case "replace":
+			if !exists {
+				return nil
+			}
+		case "cas":
+			if !exists {
+				reply = "NOT_FOUND"
+				return nil
+			}
+			if cur.cas != casUnique {
+				reply = "EXISTS"
+				return nil
+			}
+		}
+		s.save(tx, key, val, meta)
+		if !at.IsZero() {
+			tx.SetExpiry(key, at)
+		}
+		reply = "STORED"
+		return nil
+	})
+	writeReply(w, reply, noreply(args, n))
+	return false
+}
+
+func (s *MemcachedServer) delete(key string) bool {
+	deleted := false
+	_ = s.m.Tx([]string{key}, func(tx hashmap.TxView[string, []byte]) error {
+		if _, _, ok := s.load(tx, key); ok {
+			tx.Remove(key)
+			s.meta.Remove(key)
+			deleted = true
+		}
+		return nil
+	})
+	return deleted
+}
+
+func (s *MemcachedServer) incr(key string, delta uint64, incr bool) string {
+	reply := "NOT_FOUND"
+	_ = s.m.Tx([]string{key}, func(tx hashmap.TxView[string, []byte]) error {
+		val, meta, ok := s.load(tx, key)
+		if !ok {
+			return nil
+		}
+		n, err := strconv.ParseUint(strings.TrimSpace(string(val)), 10, 64)
+		if err != nil {
+			reply = "CLIENT_ERROR cannot increment or decrement non-numeric value"
+			return nil
+		}
+		if incr {
+			n += delta
+		} else if delta > n {
+			n = 0
+		} else {
+			n -= delta
+		}
+		reply = strconv.FormatUint(n, 10)
+		// incr and decr keep the expiration time of the item
+		at, _ := tx.Expiry(key)
+		s.save(tx, key, []byte(reply), meta)
+		tx.SetExpiry(key, at)
+		return nil
+	})
+	return reply
+}
+
+// load returns the value and the metadata of the key, removing the key if it is expired.
+func (s *MemcachedServer) load(tx hashmap.TxView[string, []byte], key string) ([]byte, itemMeta, bool) {
+	val, ok := tx.Get(key)
+	if !ok {
+		return nil, itemMeta{}, false
+	}
+	if at, ok := tx.Expiry(key); ok && !at.After(time.Now()) {
+		tx.Remove(key)
+		s.meta.Remove(key)
+		return nil, itemMeta{}, false
+	}
+	meta, _ := s.meta.Get(key)
+	return val, meta, true
+}
+
+// save puts the value with the metadata and a new cas value.
+func (s *MemcachedServer) save(tx hashmap.TxView[string, []byte], key string, val []byte, meta itemMeta) {
+	meta.cas = atomic.AddUint64(s.cas, 1)
+	tx.Put(key, val)
+	s.meta.Put(key, meta)
+}
+
+// expireAt converts the exptime of the protocol to a time, the zero time means no expiration.
+func expireAt(exptime int64) time.Time {
+	switch {
+	case exptime == 0:
+		return time.Time{}
+	case exptime < 0:
+		return time.Unix(0, 1)
+	case exptime <= maxRelativeExptime:
+		return time.Now().Add(time.Duration(exptime) * time.Second)
+	case exptime > math.MaxInt64/int64(time.Second):
+		// a timestamp too far to be represented never expires
+		return time.Unix(0, math.MaxInt64)
+	default:
+		return time.Unix(exptime, 0)
+	}
+}
+
+func validKey(key string) bool {
+	if len(key) == 0 || len(key) > maxMemcachedKeyLen {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] <= ' ' || key[i] == 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+func noreply(args []string, i int) bool {
+	return len(args) > i && args[i] == "noreply"
+}
+
+func writeReply(w *bufio.Writer, reply string, noreply bool) {
+	if !noreply {
+		_, _ = w.WriteString(reply + "\r\n")
+	}
+}
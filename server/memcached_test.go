@@ -0,0 +1,243 @@
+package server
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/semihbkgr/hashmap"
+)
+
+type memcachedClient struct {
+	c net.Conn
+	r *bufio.Reader
+}
+
+func startMemcachedServer(t *testing.T) (*hashmap.ConcurrentHashMap[string, []byte], func() *memcachedClient) {
+	m := hashmap.NewString[[]byte]()
+	addr := serveLoopback(t, NewMemcachedServer(&m))
+	return &m, func() *memcachedClient {
+		return dialMemcached(t, addr)
+	}
+}
+
+func dialMemcached(t *testing.T, addr string) *memcachedClient {
+	c, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &memcachedClient{c: c, r: bufio.NewReader(c)}
+}
+
+// do sends the command and reads the reply lines until a line with one of the terminators.
+func (mc *memcachedClient) do(t *testing.T, cmd string, terminators ...string) []string {
+	if _, err := mc.c.Write([]byte(cmd)); err != nil {
+		t.Fatal(err)
+	}
+	var lines []string
+	for {
+		line, err := readLine(mc.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, line)
+		if len(terminators) == 0 {
+			return lines
+		}
+		for _, term := range terminators {
+			if line == term {
+				return lines
+			}
+		}
+	}
+}
+
+func TestMemcachedServer_Storage(t *testing.T) {
+	m, dial := startMemcachedServer(t)
+	mc := dial()
+	if r := mc.do(t, "set a 5 0 5\r\nhello\r\n"); r[0] != "STORED" {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	if v, _ := m.Get("a"); string(v) != "hello" {
+		t.FailNow()
+	}
+	if r := mc.do(t, "add a 0 0 1\r\nx\r\n"); r[0] != "NOT_STORED" {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	if r := mc.do(t, "replace b 0 0 1\r\nx\r\n"); r[0] != "NOT_STORED" {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	if r := mc.do(t, "add b 7 0 2\r\nhi\r\n"); r[0] != "STORED" {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	r := mc.do(t, "get a b c\r\n", "END")
+	expected := []string{"VALUE a 5 5", "hello", "VALUE b 7 2", "hi", "END"}
+	if strings.Join(r, "|") != strings.Join(expected, "|") {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	r = mc.do(t, "gets a\r\n", "END")
+	fields := strings.Fields(r[0])
+	if len(fields) != 5 {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	cas := fields[4]
+	if r := mc.do(t, "cas a 0 0 3 "+cas+"\r\nbye\r\n"); r[0] != "STORED" {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	if r := mc.do(t, "cas a 0 0 3 "+cas+"\r\nbad\r\n"); r[0] != "EXISTS" {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	if r := mc.do(t, "cas z 0 0 3 1\r\nbad\r\n"); r[0] != "NOT_FOUND" {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	if r := mc.do(t, "replace a 1 0 3\r\nnew\r\n"); r[0] != "STORED" {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	if r := mc.do(t, "get a\r\n", "END"); r[0] != "VALUE a 1 3" || r[1] != "new" {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	if r := mc.do(t, "delete a\r\n"); r[0] != "DELETED" {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	if r := mc.do(t, "delete a\r\n"); r[0] != "NOT_FOUND" {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	if r := mc.do(t, "set a 0 0 1 noreply\r\nx\r\nget a\r\n", "END"); r[0] != "VALUE a 0 1" {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	if r := mc.do(t, "set a x 0 1\r\nx\r\n"); !strings.HasPrefix(r[0], "CLIENT_ERROR") {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	if r := mc.do(t, "unknown\r\n"); r[0] != "ERROR" {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+}
+
+func TestMemcachedServer_IncrDecr(t *testing.T) {
+	_, dial := startMemcachedServer(t)
+	mc := dial()
+	mc.do(t, "set n 0 0 2\r\n10\r\n")
+	if r := mc.do(t, "incr n 5\r\n"); r[0] != "15" {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	if r := mc.do(t, "decr n 20\r\n"); r[0] != "0" {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	if r := mc.do(t, "incr x 1\r\n"); r[0] != "NOT_FOUND" {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	mc.do(t, "set s 0 0 1\r\na\r\n")
+	if r := mc.do(t, "incr s 1\r\n"); !strings.HasPrefix(r[0], "CLIENT_ERROR") {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+
+	var wg sync.WaitGroup
+	incr := func() {
+		defer wg.Done()
+		c := dial()
+		for i := 0; i < 100; i++ {
+			c.do(t, "incr n 1\r\n")
+		}
+	}
+	wg.Add(3)
+	go incr()
+	go incr()
+	go incr()
+	wg.Wait()
+	if r := mc.do(t, "get n\r\n", "END"); r[1] != "300" {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+}
+
+func TestMemcachedServer_Expire(t *testing.T) {
+	m, dial := startMemcachedServer(t)
+	mc := dial()
+	mc.do(t, "set a 0 -1 1\r\nx\r\n")
+	if r := mc.do(t, "get a\r\n", "END"); r[0] != "END" || m.Contains("a") {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	mc.do(t, "set b 0 1 1\r\nx\r\n")
+	if r := mc.do(t, "get b\r\n", "END"); r[0] != "VALUE b 0 1" {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	time.Sleep(1100 * time.Millisecond)
+	if r := mc.do(t, "get b\r\n", "END"); r[0] != "END" {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	if r := mc.do(t, "add b 0 0 1\r\ny\r\n"); r[0] != "STORED" {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	// a timestamp beyond the range of time must not expire the item immediately
+	mc.do(t, "set c 0 9223372036854775807 1\r\nx\r\n")
+	if r := mc.do(t, "get c\r\n", "END"); r[0] != "VALUE c 0 1" {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	if at, ok := m.Expiry("c"); !ok || !at.After(time.Now().Add(100*365*24*time.Hour)) {
+		t.Logf("expiry: %v", at)
+		t.FailNow()
+	}
+}
+
+func TestMemcachedServer_RESP_Expire(t *testing.T) {
+	m := hashmap.NewString[[]byte]()
+	rc := dialRESP(t, serveLoopback(t, NewRESPServer(&m)))
+	mc := dialMemcached(t, serveLoopback(t, NewMemcachedServer(&m)))
+	// a key written through memcached must not keep the expiration time set through RESP
+	rc.do(t, "SET", "a", "old", "PX", "100")
+	if r := mc.do(t, "delete a\r\n"); r[0] != "DELETED" {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	if r := mc.do(t, "set a 0 0 3\r\nnew\r\n"); r[0] != "STORED" {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	// and the other way around
+	mc.do(t, "set b 0 1 3\r\nold\r\n")
+	rc.do(t, "SET", "b", "new")
+	if r := rc.do(t, "TTL", "b"); r != int64(-1) {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	// wait for the expire loop of the RESP server
+	time.Sleep(expireInterval + 200*time.Millisecond)
+	for _, key := range []string{"a", "b"} {
+		if r := rc.do(t, "GET", key); r != "new" {
+			t.Logf("key: %s, reply: %v", key, r)
+			t.FailNow()
+		}
+		if r := mc.do(t, "get "+key+"\r\n", "END"); len(r) != 3 || r[1] != "new" {
+			t.Logf("key: %s, reply: %v", key, r)
+			t.FailNow()
+		}
+	}
+}
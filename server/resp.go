@@ -0,0 +1,554 @@
+// Package server serves ConcurrentHashMap over network protocols.
+package server
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/semihbkgr/hashmap"
+)
+
+const (
+	defaultScanCount = 10
+	expireInterval   = time.Second
+	maxBulkLen       = 512 << 20
+)
+
+// ErrServerClosed is returned by Serve after the server is closed.
+var ErrServerClosed = errors.New("server closed")
+
+var errProtocol = errors.New("protocol error")
+
+// RESPServer serves a string type key ConcurrentHashMap over the RESP protocol, so that redis clients can use it.
+// Supported commands are GET, SET, DEL, EXISTS, DBSIZE, SCAN, EXPIRE, TTL, MGET, PING, SELECT, COMMAND and QUIT.
+type RESPServer struct {
+	m     *hashmap.ConcurrentHashMap[string, []byte]
+	conns *conns
+}
+
+// NewRESPServer returns RESPServer serving the given map.
+func NewRESPServer(m *hashmap.ConcurrentHashMap[string, []byte]) *RESPServer {
+	return &RESPServer{
+		m:     m,
+		conns: newConns(),
+	}
+}
+
+// ListenAndServe listens on the given TCP address and serves connections.
+func (s *RESPServer) ListenAndServe(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(l)
+}
+
+// Serve accepts connections on the listener and serves each of them in its own goroutine.
+// It returns when the listener fails or the server is closed.
+func (s *RESPServer) Serve(l net.Listener) error {
+	stop := make(chan struct{})
+	defer close(stop)
+	go s.expireLoop(stop)
+	return s.conns.serve(l, s.serveConn)
+}
+
+// Close closes the listeners and connections of the server.
+func (s *RESPServer) Close() error {
+	return s.conns.close()
+}
+
+func (s *RESPServer) serveConn(c net.Conn) {
+	r := bufio.NewReader(c)
+	w := bufio.NewWriter(c)
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			if err == errProtocol {
+				writeError(w, "ERR Protocol error")
+				_ = w.Flush()
+			}
+			return
+		}
+		if len(args) == 0 {
+			continue
+		}
+		quit := s.exec(w, args)
+		if r.Buffered() == 0 || quit {
+			if err := w.Flush(); err != nil || quit {
+				return
+			}
+		}
+	}
+}
+
+func (s *RESPServer) exec(w *bufio.Writer, args []string) bool {
+	cmd := strings.ToUpper(args[0])
+	args = args[1:]
+	switch cmd {
+	case "PING":
+		if len(args) > 0 {
+			writeBulk(w, []byte(args[0]))
+		} else {
+			writeSimple(w, "PONG")
+		}
+	case "QUIT":
+		writeSimple(w, "OK")
+		return true
+	case "SELECT":
+		if len(args) != 1 || args[0] != "0" {
+			writeError(w, "ERR DB index is out of range")
+		} else {
+			writeSimple(w, "OK")
+		}
+	case "COMMAND":
+		writeArrayLen(w, 0)
+	case "GET":
+		if !checkArgs(w, cmd, args, 1, 1) {
+			break
+		}
+		if val, ok := s.get(args[0]); ok {
+			writeBulk(w, val)
+		} else {
+			writeNull(w)
+		}
+	case "MGET":
+		if !checkArgs(w, cmd, args, 1, -1) {
+			break
+		}
+		s.mget(w, args)
+	case "SET":
+		if !checkArgs(w, cmd, args, 2, -1) {
+			break
+		}
+		s.set(w, args)
+	case "DEL":
+		if !checkArgs(w, cmd, args, 1, -1) {
+			break
+		}
+		writeInt(w, int64(s.del(args)))
+	case "EXISTS":
+		if !checkArgs(w, cmd, args, 1, -1) {
+			break
+		}
+		n := 0
+		for _, key := range args {
+			if _, ok := s.get(key); ok {
+				n++
+			}
+		}
+		writeInt(w, int64(n))
+	case "DBSIZE":
+		if !checkArgs(w, cmd, args, 0, 0) {
+			break
+		}
+		writeInt(w, int64(s.m.Size()))
+	case "EXPIRE":
+		if !checkArgs(w, cmd, args, 2, 2) {
+			break
+		}
+		seconds, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			writeError(w, "ERR value is not an integer or out of range")
+			break
+		}
+		at, ok := expireTime(seconds, time.Second)
+		if !ok {
+			writeError(w, "ERR invalid expire time in 'expire' command")
+			break
+		}
+		if s.expire(args[0], at) {
+			writeInt(w, 1)
+		} else {
+			writeInt(w, 0)
+		}
+	case "TTL":
+		if !checkArgs(w, cmd, args, 1, 1) {
+			break
+		}
+		writeInt(w, s.ttl(args[0]))
+	case "SCAN":
+		if !checkArgs(w, cmd, args, 1, -1) {
+			break
+		}
+		s.scan(w, args)
+	default:
+		writeError(w, fmt.Sprintf("ERR unknown command '%s'", strings.ToLower(cmd)))
+	}
+	return false
+}
+
+func checkArgs(w *bufio.Writer, cmd string, args []string, min, max int) bool {
+	if len(args) < min || (max >= 0 && len(args) > max) {
+		writeError(w, fmt.Sprintf("ERR wrong number of arguments for '%s' command", strings.ToLower(cmd)))
+		return false
+	}
+	return true
+}
+
+// get returns the value of the key, removing it if it is expired.
+func (s *RESPServer) get(key string) ([]byte, bool) {
+	if at, ok := s.m.Expiry(key); ok && !at.After(time.Now()) {
+		s.expireKey(key)
+		return nil, false
+	}
+	return s.m.Get(key)
+}
+
+func (s *RESPServer) mget(w *bufio.Writer, keys []string) {
+	now := time.Now()
+	vals, oks := s.m.GetMany(keys)
+	writeArrayLen(w, len(keys))
+	for i, key := range keys {
+		if at, ok := s.m.Expiry(key); ok && !at.After(now) {
+			s.expireKey(key)
+			oks[i] = false
+		}
+		if oks[i] {
+			writeBulk(w, vals[i])
+		} else {
+			writeNull(w)
+		}
+	}
+}
+
+func (s *RESPServer) set(w *bufio.Writer, args []string) {
+	key, val := args[0], []byte(args[1])
+	var expireAt time.Time
+	var nx, xx bool
+	for i := 2; i < len(args); i++ {
+		switch opt := strings.ToUpper(args[i]); opt {
+		case "NX":
+			nx = true
+		case "XX":
+			xx = true
+		case "EX", "PX":
+			if i+1 >= len(args) {
+				writeError(w, "ERR syntax error")
+				return
+			}
+			i++
+			unit := time.Second
+			if opt == "PX" {
+				unit = time.Millisecond
+			}
+			n, err := strconv.ParseInt(args[i], 10, 64)
+			var ok bool
+			if err == nil && n > 0 {
+				expireAt, ok = expireTime(n, unit)
+			}
+			if !ok {
+				writeError(w, "ERR invalid expire time in 'set' command")
+				return
+			}
+		default:
+			writeError(w, "ERR syntax error")
+			return
+		}
+	}
+	if nx && xx {
+		writeError(w, "ERR syntax error")
+		return
+	}
+	applied := false
+	_ = s.m.Tx([]string{key}, func(tx hashmap.TxView[string, []byte]) error {
+		_, exists := tx.Get(key)
+		if exists {
+			if at, ok := tx.Expiry(key); ok && !at.After(time.Now()) {
+				exists = false
+			}
+		}
+		if (nx && exists) || (xx && !exists) {
+			return nil
+		}
+		// putting the value clears the expiration time of the previous one
+		tx.Put(key, val)
+		if !expireAt.IsZero() {
+			tx.SetExpiry(key, expireAt)
+		}
+		applied = true
+		return nil
+	})
+	if applied {
+		writeSimple(w, "OK")
+	} else {
+		writeNull(w)
+	}
+}
+
+func (s *RESPServer) del(keys []string) int {
+	n := 0
+	for _, key := range keys {
+		if _, ok := s.get(key); !ok {
+			continue
+		}
+		_ = s.m.Tx([]string{key}, func(tx hashmap.TxView[string, []byte]) error {
+			if _, ok := tx.Remove(key); ok {
+				n++
+			}
+			return nil
+		})
+	}
+	return n
+}
+
+// expireTime returns the time n units later, it returns false if the duration overflows.
+func expireTime(n int64, unit time.Duration) (time.Time, bool) {
+	if n > math.MaxInt64/int64(unit) || n < math.MinInt64/int64(unit) {
+		return time.Time{}, false
+	}
+	return time.Now().Add(time.Duration(n) * unit), true
+}
+
+// expire sets the expiration time of the key, it returns false if there is no such key.
+func (s *RESPServer) expire(key string, at time.Time) bool {
+	if _, ok := s.get(key); !ok {
+		return false
+	}
+	set := false
+	_ = s.m.Tx([]string{key}, func(tx hashmap.TxView[string, []byte]) error {
+		set = tx.SetExpiry(key, at)
+		return nil
+	})
+	if set && !at.After(time.Now()) {
+		s.expireKey(key)
+	}
+	return set
+}
+
+func (s *RESPServer) ttl(key string) int64 {
+	if _, ok := s.get(key); !ok {
+		return -2
+	}
+	at, ok := s.m.Expiry(key)
+	if !ok {
+		return -1
+	}
+	return int64(time.Until(at).Round(time.Second) / time.Second)
+}
+
+// expireKey removes the key if it is still expired.
+func (s *RESPServer) expireKey(key string) {
+	_ = s.m.Tx([]string{key}, func(tx hashmap.TxView[string, []byte]) error {
+		if at, ok := tx.Expiry(key); ok && !at.After(time.Now()) {
+			tx.Remove(key)
+		}
+		return nil
+	})
+}
+
+func (s *RESPServer) expireLoop(stop <-chan struct{}) {
+	ticker := time.NewTicker(expireInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		s.m.RemoveExpired(time.Now())
+	}
+}
+
+func (s *RESPServer) scan(w *bufio.Writer, args []string) {
+	cursor, err := strconv.Atoi(args[0])
+	if err != nil || cursor < 0 {
+		writeError(w, "ERR invalid cursor")
+		return
+	}
+	pattern := ""
+	count := defaultScanCount
+	for i := 1; i < len(args); i += 2 {
+		if i+1 >= len(args) {
+			writeError(w, "ERR syntax error")
+			return
+		}
+		switch strings.ToUpper(args[i]) {
+		case "MATCH":
+			pattern = args[i+1]
+		case "COUNT":
+			count, err = strconv.Atoi(args[i+1])
+			if err != nil || count <= 0 {
+				writeError(w, "ERR syntax error")
+				return
+			}
+		default:
+			writeError(w, "ERR syntax error")
+			return
+		}
+	}
+	entries, next := s.m.Scan(cursor, count)
+	now := time.Now()
+	keys := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if at, ok := s.m.Expiry(e.Key); ok && !at.After(now) {
+			continue
+		}
+		if pattern != "" {
+			if !globMatch(pattern, e.Key) {
+				continue
+			}
+		}
+		keys = append(keys, e.Key)
+	}
+	writeArrayLen(w, 2)
+	writeBulk(w, []byte(strconv.Itoa(next)))
+	writeArrayLen(w, len(keys))
+	for _, key := range keys {
+		writeBulk(w, []byte(key))
+	}
+}
+
+// readCommand reads a command either as an array of bulk strings or as an inline command.
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 || line[0] != '*' {
+		return strings.Fields(line), nil
+	}
+	n, err := strconv.Atoi(line[1:])
+	if err != nil || n > 1024*1024 {
+		return nil, errProtocol
+	}
+	if n <= 0 {
+		// a null or empty array is ignored, as redis does
+		return nil, nil
+	}
+	args := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		line, err = readLine(r)
+		if err != nil {
+			return nil, err
+		}
+		if len(line) == 0 || line[0] != '$' {
+			return nil, errProtocol
+		}
+		l, err := strconv.Atoi(line[1:])
+		if err != nil || l < 0 || l > maxBulkLen {
+			return nil, errProtocol
+		}
+		buf := make([]byte, l+2)
+		if _, err = io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		if buf[l] != '\r' || buf[l+1] != '\n' {
+			return nil, errProtocol
+		}
+		args = append(args, string(buf[:l]))
+	}
+	return args, nil
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func writeSimple(w *bufio.Writer, s string) {
+	_, _ = w.WriteString("+" + s + "\r\n")
+}
+
+func writeError(w *bufio.Writer, s string) {
+	_, _ = w.WriteString("-" + s + "\r\n")
+}
+
+func writeInt(w *bufio.Writer, n int64) {
+	_, _ = w.WriteString(":" + strconv.FormatInt(n, 10) + "\r\n")
+}
+
+func writeBulk(w *bufio.Writer, b []byte) {
+	_, _ = w.WriteString("$" + strconv.Itoa(len(b)) + "\r\n")
+	_, _ = w.Write(b)
+	_, _ = w.WriteString("\r\n")
+}
+
+func writeNull(w *bufio.Writer) {
+	_, _ = w.WriteString("$-1\r\n")
+}
+
+func writeArrayLen(w *bufio.Writer, n int) {
+	_, _ = w.WriteString("*" + strconv.Itoa(n) + "\r\n")
+}
+
+// conns tracks the listeners and connections of a server to close them.
+type conns struct {
+	sync.Mutex
+	closed    bool
+	listeners map[net.Listener]struct{}
+	active    map[net.Conn]struct{}
+}
+
+func newConns() *conns {
+	return &conns{
+		listeners: make(map[net.Listener]struct{}),
+		active:    make(map[net.Conn]struct{}),
+	}
+}
+
+func (cs *conns) serve(l net.Listener, handle func(c net.Conn)) error {
+	cs.Lock()
+	if cs.closed {
+		cs.Unlock()
+		return ErrServerClosed
+	}
+	cs.listeners[l] = struct{}{}
+	cs.Unlock()
+	for {
+		c, err := l.Accept()
+		if err != nil {
+			cs.Lock()
+			closed := cs.closed
+			delete(cs.listeners, l)
+			cs.Unlock()
+			if closed {
+				return ErrServerClosed
+			}
+			return err
+		}
+		cs.Lock()
+		if cs.closed {
+			cs.Unlock()
+			_ = c.Close()
+			return ErrServerClosed
+		}
+		cs.active[c] = struct{}{}
+		cs.Unlock()
+		go func() {
+			defer func() {
+				cs.Lock()
+				delete(cs.active, c)
+				cs.Unlock()
+				_ = c.Close()
+			}()
+			handle(c)
+		}()
+	}
+}
+
+func (cs *conns) close() error {
+	cs.Lock()
+	defer cs.Unlock()
+	cs.closed = true
+	var err error
+	for l := range cs.listeners {
+		if lerr := l.Close(); lerr != nil && err == nil {
+			err = lerr
+		}
+	}
+	for c := range cs.active {
+		_ = c.Close()
+	}
+	return err
+}
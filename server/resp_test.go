@@ -0,0 +1,273 @@
+package server
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/semihbkgr/hashmap"
+)
+
+type respClient struct {
+	c net.Conn
+	r *bufio.Reader
+}
+
+type server interface {
+	Serve(l net.Listener) error
+	Close() error
+}
+
+// serveLoopback serves s on a loopback listener until the test ends and returns the address of the listener.
+func serveLoopback(t *testing.T, s server) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		_ = s.Serve(l)
+	}()
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+	return l.Addr().String()
+}
+
+func startRESPServer(t *testing.T) (*hashmap.ConcurrentHashMap[string, []byte], *respClient) {
+	m := hashmap.NewString[[]byte]()
+	return &m, dialRESP(t, serveLoopback(t, NewRESPServer(&m)))
+}
+
+func dialRESP(t *testing.T, addr string) *respClient {
+	c, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &respClient{c: c, r: bufio.NewReader(c)}
+}
+
+func (rc *respClient) do(t *testing.T, args ...string) any {
+	cmd := "*" + strconv.Itoa(len(args)) + "\r\n"
+	for _, a := range args {
+		cmd += "$" + strconv.Itoa(len(a)) + "\r\n" + a + "\r\n"
+	}
+	if _, err := rc.c.Write([]byte(cmd)); err != nil {
+		t.Fatal(err)
+	}
+	reply, err := rc.read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reply
+}
+
+func (rc *respClient) read() (any, error) {
+	line, err := readLine(rc.r)
+	if err != nil {
+		return nil, err
+	}
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return fmt.Errorf("%s", line[1:]), nil
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, _ := strconv.Atoi(line[1:])
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err = io.ReadFull(rc.r, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, _ := strconv.Atoi(line[1:])
+		arr := make([]any, n)
+		for i := range arr {
+			if arr[i], err = rc.read(); err != nil {
+				return nil, err
+			}
+		}
+		return arr, nil
+	}
+	return nil, fmt.Errorf("unexpected reply: %s", line)
+}
+
+func TestRESPServer(t *testing.T) {
+	m, rc := startRESPServer(t)
+	if r := rc.do(t, "PING"); r != "PONG" {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	if r := rc.do(t, "SET", "a", "1"); r != "OK" {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	if r := rc.do(t, "SET", "a", "2", "NX"); r != nil {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	if r := rc.do(t, "SET", "b", "2", "XX"); r != nil {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	if r := rc.do(t, "GET", "a"); r != "1" {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	if v, _ := m.Get("a"); string(v) != "1" {
+		t.FailNow()
+	}
+	m.Put("b", []byte("2"))
+	r := rc.do(t, "MGET", "a", "b", "c")
+	if arr, ok := r.([]any); !ok || len(arr) != 3 || arr[0] != "1" || arr[1] != "2" || arr[2] != nil {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	if r := rc.do(t, "EXISTS", "a", "b", "c"); r != int64(2) {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	if r := rc.do(t, "DBSIZE"); r != int64(2) {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	if r := rc.do(t, "DEL", "a", "c"); r != int64(1) {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	if r := rc.do(t, "GET", "a"); r != nil {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	if _, ok := rc.do(t, "GET").(error); !ok {
+		t.FailNow()
+	}
+	if _, ok := rc.do(t, "FLUSHALL").(error); !ok {
+		t.FailNow()
+	}
+}
+
+func TestRESPServer_Expire(t *testing.T) {
+	m, rc := startRESPServer(t)
+	rc.do(t, "SET", "a", "1")
+	if r := rc.do(t, "TTL", "a"); r != int64(-1) {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	if r := rc.do(t, "EXPIRE", "a", "100"); r != int64(1) {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	if r := rc.do(t, "TTL", "a"); r != int64(100) {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	if r := rc.do(t, "EXPIRE", "b", "100"); r != int64(0) {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	rc.do(t, "SET", "a", "1")
+	if r := rc.do(t, "TTL", "a"); r != int64(-1) {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	rc.do(t, "SET", "b", "1", "PX", "20")
+	time.Sleep(30 * time.Millisecond)
+	if r := rc.do(t, "GET", "b"); r != nil {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	if m.Contains("b") {
+		t.FailNow()
+	}
+	if r := rc.do(t, "EXPIRE", "a", "0"); r != int64(1) || m.Contains("a") {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+}
+
+func TestRESPServer_Expire_Overflow(t *testing.T) {
+	m, rc := startRESPServer(t)
+	rc.do(t, "SET", "a", "1")
+	for _, args := range [][]string{
+		{"EXPIRE", "a", "10000000000"},
+		{"EXPIRE", "a", "-10000000000"},
+		{"SET", "a", "2", "EX", "10000000000"},
+		{"SET", "a", "2", "PX", "9223372036854775807"},
+	} {
+		if r, ok := rc.do(t, args...).(error); !ok || !strings.Contains(r.Error(), "invalid expire time") {
+			t.Logf("args: %v, reply: %v", args, r)
+			t.FailNow()
+		}
+	}
+	if v, ok := m.Get("a"); !ok || string(v) != "1" {
+		t.FailNow()
+	}
+	if r := rc.do(t, "EXPIRE", "a", "9223372036"); r != int64(1) {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+	if r := rc.do(t, "TTL", "a"); r.(int64) <= 0 {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+}
+
+func TestRESPServer_Scan(t *testing.T) {
+	m, rc := startRESPServer(t)
+	for i := 0; i < 100; i++ {
+		m.Put("user:"+strconv.Itoa(i), []byte(strconv.Itoa(i)))
+		m.Put("session:"+strconv.Itoa(i), []byte(strconv.Itoa(i)))
+	}
+	m.Put("user:1/profile", []byte("1"))
+	seen := make(map[string]bool)
+	cursor := "0"
+	for {
+		r := rc.do(t, "SCAN", cursor, "MATCH", "user:*", "COUNT", "20")
+		arr := r.([]any)
+		cursor = arr[0].(string)
+		for _, key := range arr[1].([]any) {
+			seen[key.(string)] = true
+		}
+		if cursor == "0" {
+			break
+		}
+	}
+	if len(seen) != 101 || !seen["user:42"] || !seen["user:1/profile"] {
+		t.Logf("seen: %d", len(seen))
+		t.FailNow()
+	}
+}
+
+func TestRESPServer_Inline_Pipeline(t *testing.T) {
+	_, rc := startRESPServer(t)
+	if _, err := rc.c.Write([]byte("SET a 1\r\nGET a\r\nPING\r\n")); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []any{"OK", "1", "PONG"} {
+		if r, err := rc.read(); err != nil || r != expected {
+			t.Logf("reply: %v, err: %v", r, err)
+			t.FailNow()
+		}
+	}
+}
+
+func TestRESPServer_NullArray(t *testing.T) {
+	_, rc := startRESPServer(t)
+	if _, err := rc.c.Write([]byte("*-1\r\n*0\r\n*-5\r\n")); err != nil {
+		t.Fatal(err)
+	}
+	if r := rc.do(t, "PING"); r != "PONG" {
+		t.Logf("reply: %v", r)
+		t.FailNow()
+	}
+}
@@ -0,0 +1,120 @@
+package hashmap
+
+// ConcurrentHashSet thread-safe set sharing the bucket structure of ConcurrentHashMap.
+// Nodes hold zero-size values, so no memory is spent for values.
+type ConcurrentHashSet[k any] struct {
+	m ConcurrentHashMap[k, struct{}]
+}
+
+// NewSet returns ConcurrentHashSet with default capacity.
+func NewSet[k Hasher]() ConcurrentHashSet[k] {
+	return ConcurrentHashSet[k]{m: New[k, struct{}]()}
+}
+
+// NewStringSet returns string type ConcurrentHashSet with default capacity.
+func NewStringSet() ConcurrentHashSet[string] {
+	return ConcurrentHashSet[string]{m: NewString[struct{}]()}
+}
+
+// NewSetWithFuncs returns ConcurrentHashSet with the given funcs and default capacity.
+func NewSetWithFuncs[k any](hf HashFunc[k], ef EqualsFunc[k]) (chs ConcurrentHashSet[k], err error) {
+	chs.m, err = NewWithFuncs[k, struct{}](hf, ef)
+	return
+}
+
+// NewSetWithCap returns ConcurrentHashSet with given capacity.
+func NewSetWithCap[k Hasher](capacity int) (chs ConcurrentHashSet[k], err error) {
+	chs.m, err = NewWithCap[k, struct{}](capacity)
+	return
+}
+
+// NewStringSetWithCap returns string type ConcurrentHashSet with given capacity.
+func NewStringSetWithCap(capacity int) (chs ConcurrentHashSet[string], err error) {
+	chs.m, err = NewStringWithCap[struct{}](capacity)
+	return
+}
+
+// NewSetWithCapAndFuncs returns ConcurrentHashSet with the given capacity and funcs.
+func NewSetWithCapAndFuncs[k any](capacity int, hf HashFunc[k], ef EqualsFunc[k]) (chs ConcurrentHashSet[k], err error) {
+	chs.m, err = NewWithCapAndFuncs[k, struct{}](capacity, hf, ef)
+	return
+}
+
+// Add saves the given key and returns true if it was not in the set.
+func (s *ConcurrentHashSet[k]) Add(key k) bool {
+	return s.m.putIfAbsent(key, struct{}{})
+}
+
+// AddAll saves all the given keys, taking the lock of each involved bucket once.
+func (s *ConcurrentHashSet[k]) AddAll(keys []k) {
+	entries := make([]Entry[k, struct{}], len(keys))
+	for i, key := range keys {
+		entries[i].Key = key
+	}
+	s.m.PutAll(entries)
+}
+
+// Remove removes the given key and returns true if it was in the set.
+func (s *ConcurrentHashSet[k]) Remove(key k) bool {
+	_, ok := s.m.Remove(key)
+	return ok
+}
+
+// Contains returns if the given key is in the set.
+func (s *ConcurrentHashSet[k]) Contains(key k) bool {
+	return s.m.Contains(key)
+}
+
+// Size returns the count of keys in the set.
+func (s *ConcurrentHashSet[k]) Size() int {
+	return s.m.Size()
+}
+
+// Range calls fn for each key in the set until fn returns false.
+func (s *ConcurrentHashSet[k]) Range(fn func(key k) bool) {
+	s.m.Range(func(key k, _ struct{}) bool {
+		return fn(key)
+	})
+}
+
+// Union returns a new set containing the keys which are in either set.
+// The new set has the capacity and funcs of s.
+func (s *ConcurrentHashSet[k]) Union(other *ConcurrentHashSet[k]) ConcurrentHashSet[k] {
+	chs := ConcurrentHashSet[k]{m: s.m.Clone()}
+	var keys []k
+	other.Range(func(key k) bool {
+		keys = append(keys, key)
+		return true
+	})
+	chs.AddAll(keys)
+	return chs
+}
+
+// Intersection returns a new set containing the keys which are in both sets.
+// The new set has the capacity and funcs of s.
+func (s *ConcurrentHashSet[k]) Intersection(other *ConcurrentHashSet[k]) ConcurrentHashSet[k] {
+	return s.filter(func(key k) bool {
+		return other.Contains(key)
+	})
+}
+
+// Difference returns a new set containing the keys which are in s but not in other.
+// The new set has the capacity and funcs of s.
+func (s *ConcurrentHashSet[k]) Difference(other *ConcurrentHashSet[k]) ConcurrentHashSet[k] {
+	return s.filter(func(key k) bool {
+		return !other.Contains(key)
+	})
+}
+
+func (s *ConcurrentHashSet[k]) filter(pred func(key k) bool) ConcurrentHashSet[k] {
+	chs, _ := NewSetWithCapAndFuncs[k](int(s.m.capacity), s.m.hf, s.m.ef)
+	var keys []k
+	s.Range(func(key k) bool {
+		if pred(key) {
+			keys = append(keys, key)
+		}
+		return true
+	})
+	chs.AddAll(keys)
+	return chs
+}
@@ -0,0 +1,111 @@
+package hashmap
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentHashSet_Add_Contains_Remove(t *testing.T) {
+	s := NewStringSet()
+	for i := 0; i < 10_000; i++ {
+		if !s.Add(strconv.Itoa(i)) {
+			t.Logf("key: %d", i)
+			t.FailNow()
+		}
+	}
+	for i := 0; i < 10_000; i++ {
+		if s.Add(strconv.Itoa(i)) {
+			t.Logf("key: %d", i)
+			t.FailNow()
+		}
+	}
+	if size := s.Size(); size != 10_000 {
+		t.Logf("size: %d", size)
+		t.FailNow()
+	}
+	for i := 0; i < 5_000; i++ {
+		if !s.Remove(strconv.Itoa(i)) {
+			t.Logf("key: %d", i)
+			t.FailNow()
+		}
+	}
+	for i := 0; i < 10_000; i++ {
+		if ok := s.Contains(strconv.Itoa(i)); ok != (i >= 5_000) {
+			t.Logf("key: %d, ok: %v", i, ok)
+			t.FailNow()
+		}
+	}
+}
+
+func TestConcurrentHashSet_ConcurrentlyAdd(t *testing.T) {
+	s := NewStringSet()
+	var wg sync.WaitGroup
+	var added int64
+	var mu sync.Mutex
+	addRange := func(from, to int) {
+		defer wg.Done()
+		for i := from; i < to; i++ {
+			if s.Add(strconv.Itoa(i)) {
+				mu.Lock()
+				added++
+				mu.Unlock()
+			}
+		}
+	}
+	wg.Add(3)
+	go addRange(0, 10_000)
+	go addRange(5_000, 15_000)
+	go addRange(10_000, 20_000)
+	wg.Wait()
+	if added != 20_000 || s.Size() != 20_000 {
+		t.Logf("added: %d, size: %d", added, s.Size())
+		t.FailNow()
+	}
+}
+
+func TestConcurrentHashSet_AddAll_Range(t *testing.T) {
+	s := NewStringSet()
+	keys := make([]string, 0, 1_000)
+	for i := 0; i < 1_000; i++ {
+		keys = append(keys, strconv.Itoa(i))
+	}
+	s.AddAll(keys)
+	count := 0
+	s.Range(func(key string) bool {
+		count++
+		return true
+	})
+	if count != 1_000 {
+		t.Logf("count: %d", count)
+		t.FailNow()
+	}
+}
+
+func TestConcurrentHashSet_Union_Intersection_Difference(t *testing.T) {
+	a := NewStringSet()
+	b := NewStringSet()
+	for i := 0; i < 1_000; i++ {
+		a.Add(strconv.Itoa(i))
+	}
+	for i := 500; i < 1_500; i++ {
+		b.Add(strconv.Itoa(i))
+	}
+	union := a.Union(&b)
+	intersection := a.Intersection(&b)
+	difference := a.Difference(&b)
+	if union.Size() != 1_500 || intersection.Size() != 500 || difference.Size() != 500 {
+		t.Logf("union: %d, intersection: %d, difference: %d", union.Size(), intersection.Size(), difference.Size())
+		t.FailNow()
+	}
+	for i := 0; i < 1_500; i++ {
+		k := strconv.Itoa(i)
+		if !union.Contains(k) || intersection.Contains(k) != (i >= 500 && i < 1_000) || difference.Contains(k) != (i < 500) {
+			t.Logf("key: %s", k)
+			t.FailNow()
+		}
+	}
+	if a.Size() != 1_000 || b.Size() != 1_000 {
+		t.FailNow()
+	}
+}
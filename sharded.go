@@ -0,0 +1,356 @@
+package hashmap
+
+import (
+	"errors"
+	"hash/fnv"
+	stdsort "sort"
+	"strconv"
+	"sync"
+)
+
+const defaultVirtualNodes = 128
+
+var (
+	// ErrShardExists is returned by ShardedMap.AddShard when there is already a shard with the given name.
+	ErrShardExists = errors.New("shard already exists")
+	// ErrShardNotFound is returned by ShardedMap.RemoveShard when there is no shard with the given name.
+	ErrShardNotFound = errors.New("shard not found")
+	// ErrLastShard is returned by ShardedMap.RemoveShard when the shard is the only shard of the map.
+	ErrLastShard = errors.New("last shard cannot be removed")
+	// ErrNoShards is returned by ShardedMap.Put when no shard is added yet.
+	ErrNoShards = errors.New("sharded map has no shards")
+)
+
+// ShardedMap spreads keys over multiple maps, routing them by a consistent hash ring with virtual nodes.
+// Adding or removing a shard moves only the keys whose owner changes, in the background,
+// while the keys which are not moved yet are still served from their previous shards.
+type ShardedMap[k, v any] struct {
+	hf     HashFunc[k]
+	ef     EqualsFunc[k]
+	vnodes int
+	state  *shardedState[k, v]
+}
+
+type shardedState[k, v any] struct {
+	// mu serializes the changes of shards
+	mu        sync.Mutex
+	migration chan struct{}
+	// ringMu is read locked by the operations, so that the ring is not replaced in the middle of them
+	ringMu sync.RWMutex
+	ring   *shardRing[k, v]
+}
+
+type shard[k, v any] struct {
+	name string
+	m    *ConcurrentHashMap[k, v]
+}
+
+type shardRing[k, v any] struct {
+	points []uint32
+	owners []*shard[k, v]
+	shards []*shard[k, v]
+	// prev is the ring before the ongoing migration, nil if there is none
+	prev *shardRing[k, v]
+}
+
+// NewShardedMap returns ShardedMap with the given count of virtual nodes per shard.
+func NewShardedMap[k Hasher, v any](vnodes int) ShardedMap[k, v] {
+	hf := func(key k) uint32 {
+		return key.Hash()
+	}
+	ef := func(k1, k2 k) bool {
+		return k1.Equals(k2)
+	}
+	sm, _ := NewShardedMapWithFuncs[k, v](vnodes, hf, ef)
+	return sm
+}
+
+// NewStringShardedMap returns string type key ShardedMap with the given count of virtual nodes per shard.
+func NewStringShardedMap[v any](vnodes int) ShardedMap[string, v] {
+	sm, _ := NewShardedMapWithFuncs[string, v](vnodes, hashString, equalsString)
+	return sm
+}
+
+// NewShardedMapWithFuncs returns ShardedMap with the given count of virtual nodes per shard and funcs.
+// The shards must be created with the equivalent funcs.
+func NewShardedMapWithFuncs[k, v any](vnodes int, hf HashFunc[k], ef EqualsFunc[k]) (ShardedMap[k, v], error) {
+	if hf == nil || ef == nil {
+		return ShardedMap[k, v]{}, errors.New("hash and equals funcs cannot be nil")
+	}
+	if vnodes <= 0 {
+		vnodes = defaultVirtualNodes
+	}
+	return ShardedMap[k, v]{
+		hf:     hf,
+		ef:     ef,
+		vnodes: vnodes,
+		state:  &shardedState[k, v]{ring: &shardRing[k, v]{}},
+	}, nil
+}
+
+// AddShard adds the given map as a shard, and starts moving the keys it owns from the other shards.
+// It waits for the previous migration to finish first.
+func (sm *ShardedMap[k, v]) AddShard(name string, m *ConcurrentHashMap[k, v]) error {
+	if m == nil {
+		return errors.New("shard map cannot be nil")
+	}
+	return sm.change(func(shards []*shard[k, v]) ([]*shard[k, v], error) {
+		for _, s := range shards {
+			if s.name == name {
+				return nil, ErrShardExists
+			}
+		}
+		return append(shards[:len(shards):len(shards)], &shard[k, v]{name: name, m: m}), nil
+	})
+}
+
+// RemoveShard removes the shard with the given name, and starts moving its keys to the other shards.
+// It waits for the previous migration to finish first.
+func (sm *ShardedMap[k, v]) RemoveShard(name string) error {
+	return sm.change(func(shards []*shard[k, v]) ([]*shard[k, v], error) {
+		ns := make([]*shard[k, v], 0, len(shards))
+		for _, s := range shards {
+			if s.name != name {
+				ns = append(ns, s)
+			}
+		}
+		if len(ns) == len(shards) {
+			return nil, ErrShardNotFound
+		}
+		if len(ns) == 0 {
+			return nil, ErrLastShard
+		}
+		return ns, nil
+	})
+}
+
+// Wait waits for the ongoing migration to finish.
+func (sm *ShardedMap[k, v]) Wait() {
+	sm.state.mu.Lock()
+	migration := sm.state.migration
+	sm.state.mu.Unlock()
+	if migration != nil {
+		<-migration
+	}
+}
+
+// Shards returns the names of the shards.
+func (sm *ShardedMap[k, v]) Shards() []string {
+	sm.state.ringMu.RLock()
+	defer sm.state.ringMu.RUnlock()
+	names := make([]string, len(sm.state.ring.shards))
+	for i, s := range sm.state.ring.shards {
+		names[i] = s.name
+	}
+	return names
+}
+
+func (sm *ShardedMap[k, v]) change(fn func(shards []*shard[k, v]) ([]*shard[k, v], error)) error {
+	sm.state.mu.Lock()
+	defer sm.state.mu.Unlock()
+	if sm.state.migration != nil {
+		<-sm.state.migration
+	}
+	prev := sm.state.ring
+	shards, err := fn(prev.shards)
+	if err != nil {
+		return err
+	}
+	ring := sm.newRing(shards)
+	if len(prev.shards) > 0 {
+		ring.prev = prev
+	}
+	sm.state.ringMu.Lock()
+	sm.state.ring = ring
+	sm.state.ringMu.Unlock()
+	migration := make(chan struct{})
+	sm.state.migration = migration
+	go func() {
+		defer close(migration)
+		if ring.prev != nil {
+			sm.migrate(ring)
+		}
+		sm.state.ringMu.Lock()
+		ring.prev = nil
+		sm.state.ringMu.Unlock()
+	}()
+	return nil
+}
+
+// migrate moves the entries whose owner changed from their previous shards to their current shards.
+func (sm *ShardedMap[k, v]) migrate(ring *shardRing[k, v]) {
+	for _, s := range ring.prev.shards {
+		s.m.Range(func(key k, val v) bool {
+			h := sm.hash(key)
+			if ring.prev.owner(h) != s {
+				return true
+			}
+			if to := ring.owner(h); to != s {
+				s.m.Compute(key, func(val v, ok bool) (v, bool) {
+					if ok {
+						to.m.putIfAbsent(key, val)
+					}
+					return val, false
+				})
+			}
+			return true
+		})
+	}
+}
+
+func (sm *ShardedMap[k, v]) newRing(shards []*shard[k, v]) *shardRing[k, v] {
+	type point struct {
+		hash  uint32
+		shard *shard[k, v]
+	}
+	points := make([]point, 0, len(shards)*sm.vnodes)
+	for _, s := range shards {
+		for i := 0; i < sm.vnodes; i++ {
+			h := fnv.New32a()
+			_, _ = h.Write([]byte(s.name + "#" + strconv.Itoa(i)))
+			points = append(points, point{hash: fmix32(h.Sum32()), shard: s})
+		}
+	}
+	stdsort.Slice(points, func(i, j int) bool {
+		if points[i].hash != points[j].hash {
+			return points[i].hash < points[j].hash
+		}
+		return points[i].shard.name < points[j].shard.name
+	})
+	ring := &shardRing[k, v]{
+		points: make([]uint32, len(points)),
+		owners: make([]*shard[k, v], len(points)),
+		shards: shards,
+	}
+	for i, p := range points {
+		ring.points[i] = p.hash
+		ring.owners[i] = p.shard
+	}
+	return ring
+}
+
+// owner returns the shard of the first point at or after the hash on the ring.
+func (r *shardRing[k, v]) owner(h uint32) *shard[k, v] {
+	if len(r.points) == 0 {
+		return nil
+	}
+	i := stdsort.Search(len(r.points), func(i int) bool {
+		return r.points[i] >= h
+	})
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.owners[i]
+}
+
+// hash mixes the hash of the key, so that the ring does not depend on the distribution of the low bits
+// which the shards use to pick buckets.
+func (sm *ShardedMap[k, v]) hash(key k) uint32 {
+	return fmix32(sm.hf(key))
+}
+
+func fmix32(h uint32) uint32 {
+	h ^= h >> 16
+	h *= 0x85ebca6b
+	h ^= h >> 13
+	h *= 0xc2b2ae35
+	h ^= h >> 16
+	return h
+}
+
+// route returns the current and the previous owners of the key, the previous owner is nil if it is not changed.
+// The caller must hold the read lock of the ring.
+func (sm *ShardedMap[k, v]) route(key k) (*shard[k, v], *shard[k, v]) {
+	ring := sm.state.ring
+	h := sm.hash(key)
+	cur := ring.owner(h)
+	if ring.prev == nil {
+		return cur, nil
+	}
+	if prev := ring.prev.owner(h); prev != cur {
+		return cur, prev
+	}
+	return cur, nil
+}
+
+// Put maps the given key to the value, it returns ErrNoShards if no shard is added yet.
+func (sm *ShardedMap[k, v]) Put(key k, val v) error {
+	sm.state.ringMu.RLock()
+	defer sm.state.ringMu.RUnlock()
+	cur, prev := sm.route(key)
+	if cur == nil {
+		return ErrNoShards
+	}
+	if prev == nil {
+		cur.m.Put(key, val)
+		return nil
+	}
+	// changes are made under the lock of the previous owner, as migration does, so that migration cannot overwrite them
+	prev.m.Compute(key, func(old v, ok bool) (v, bool) {
+		cur.m.Put(key, val)
+		return old, false
+	})
+	return nil
+}
+
+// Get returns value of the entry mapped by given key.
+func (sm *ShardedMap[k, v]) Get(key k) (v, bool) {
+	sm.state.ringMu.RLock()
+	defer sm.state.ringMu.RUnlock()
+	cur, prev := sm.route(key)
+	if cur == nil {
+		return *new(v), false
+	}
+	// an entry leaves the previous owner only after it is in the current owner, so the previous owner is read first
+	if prev != nil {
+		if val, ok := prev.m.Get(key); ok {
+			return val, true
+		}
+	}
+	return cur.m.Get(key)
+}
+
+// Contains returns if there is an entry mapped by the given key.
+func (sm *ShardedMap[k, v]) Contains(key k) bool {
+	_, ok := sm.Get(key)
+	return ok
+}
+
+// Remove removes the entry mapped by the given key and returns its value.
+func (sm *ShardedMap[k, v]) Remove(key k) (v, bool) {
+	sm.state.ringMu.RLock()
+	defer sm.state.ringMu.RUnlock()
+	cur, prev := sm.route(key)
+	if cur == nil {
+		return *new(v), false
+	}
+	if prev == nil {
+		return cur.m.Remove(key)
+	}
+	var val v
+	var found bool
+	prev.m.Compute(key, func(old v, ok bool) (v, bool) {
+		val, found = old, ok
+		if cv, cok := cur.m.Remove(key); cok {
+			val, found = cv, true
+		}
+		return old, false
+	})
+	return val, found
+}
+
+// Size returns the count of entries in the shards.
+func (sm *ShardedMap[k, v]) Size() int {
+	sm.state.ringMu.RLock()
+	defer sm.state.ringMu.RUnlock()
+	shards := sm.state.ring.shards
+	// a shard is either added or removed at a time, so the longer list of shards contains the other
+	if prev := sm.state.ring.prev; prev != nil && len(prev.shards) > len(shards) {
+		shards = prev.shards
+	}
+	size := 0
+	for _, s := range shards {
+		size += s.m.Size()
+	}
+	return size
+}
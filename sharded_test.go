@@ -0,0 +1,185 @@
+package hashmap
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func newShard() *ConcurrentHashMap[string, int] {
+	m := NewString[int]()
+	return &m
+}
+
+func TestShardedMap(t *testing.T) {
+	sm := NewStringShardedMap[int](0)
+	if _, ok := sm.Get("a"); ok {
+		t.FailNow()
+	}
+	shards := []*ConcurrentHashMap[string, int]{newShard(), newShard(), newShard()}
+	for i, s := range shards {
+		if err := sm.AddShard(strconv.Itoa(i), s); err != nil {
+			t.Logf("err: %v", err)
+			t.FailNow()
+		}
+	}
+	if err := sm.AddShard("0", newShard()); err != ErrShardExists {
+		t.Logf("err: %v", err)
+		t.FailNow()
+	}
+	sm.Wait()
+	for i := 0; i < 3_000; i++ {
+		sm.Put(strconv.Itoa(i), i)
+	}
+	for i := 0; i < 3_000; i++ {
+		if v, ok := sm.Get(strconv.Itoa(i)); !ok || v != i {
+			t.Logf("key: %d, value: %d, ok: %v", i, v, ok)
+			t.FailNow()
+		}
+	}
+	for i, s := range shards {
+		if s.Size() < 500 {
+			t.Logf("shard: %d, size: %d", i, s.Size())
+			t.FailNow()
+		}
+	}
+	if v, ok := sm.Remove("42"); !ok || v != 42 || sm.Contains("42") {
+		t.FailNow()
+	}
+	if sm.Size() != 2_999 {
+		t.Logf("size: %d", sm.Size())
+		t.FailNow()
+	}
+}
+
+func TestShardedMap_NoShards(t *testing.T) {
+	sm := NewStringShardedMap[int](0)
+	if err := sm.Put("a", 1); err != ErrNoShards {
+		t.Logf("err: %v", err)
+		t.FailNow()
+	}
+	if _, ok := sm.Remove("a"); ok || sm.Size() != 0 {
+		t.FailNow()
+	}
+	if err := sm.AddShard("a", nil); err == nil {
+		t.FailNow()
+	}
+	if len(sm.Shards()) != 0 {
+		t.FailNow()
+	}
+	_ = sm.AddShard("a", newShard())
+	if err := sm.Put("a", 1); err != nil {
+		t.Logf("err: %v", err)
+		t.FailNow()
+	}
+	if v, ok := sm.Get("a"); !ok || v != 1 {
+		t.FailNow()
+	}
+}
+
+func TestShardedMap_AddRemoveShard(t *testing.T) {
+	sm := NewStringShardedMap[int](64)
+	a, b := newShard(), newShard()
+	_ = sm.AddShard("a", a)
+	_ = sm.AddShard("b", b)
+	for i := 0; i < 4_000; i++ {
+		sm.Put(strconv.Itoa(i), i)
+	}
+	sizeA, sizeB := a.Size(), b.Size()
+	c := newShard()
+	_ = sm.AddShard("c", c)
+	sm.Wait()
+	moved := (sizeA - a.Size()) + (sizeB - b.Size())
+	if moved != c.Size() || moved == 0 || moved > 2_000 {
+		t.Logf("moved: %d, c: %d", moved, c.Size())
+		t.FailNow()
+	}
+	if a.Size() > sizeA || b.Size() > sizeB {
+		t.Log("keys are moved between the existing shards")
+		t.FailNow()
+	}
+	if err := sm.RemoveShard("a"); err != nil {
+		t.Logf("err: %v", err)
+		t.FailNow()
+	}
+	sm.Wait()
+	if a.Size() != 0 || sm.Size() != 4_000 {
+		t.Logf("a: %d, size: %d", a.Size(), sm.Size())
+		t.FailNow()
+	}
+	for i := 0; i < 4_000; i++ {
+		if v, ok := sm.Get(strconv.Itoa(i)); !ok || v != i {
+			t.Logf("key: %d, value: %d, ok: %v", i, v, ok)
+			t.FailNow()
+		}
+	}
+	if err := sm.RemoveShard("a"); err != ErrShardNotFound {
+		t.FailNow()
+	}
+	_ = sm.RemoveShard("b")
+	if err := sm.RemoveShard("c"); err != ErrLastShard {
+		t.FailNow()
+	}
+	if names := sm.Shards(); len(names) != 1 || names[0] != "c" {
+		t.Logf("shards: %v", names)
+		t.FailNow()
+	}
+}
+
+func TestShardedMap_ConcurrentlyMigrate(t *testing.T) {
+	sm := NewStringShardedMap[int](32)
+	_ = sm.AddShard("0", newShard())
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 2_000; i++ {
+				key := strconv.Itoa(w*10_000 + i)
+				sm.Put(key, i)
+				if v, ok := sm.Get(key); !ok || v != i {
+					t.Errorf("key: %s, value: %d, ok: %v", key, v, ok)
+					return
+				}
+				if i%4 == 0 {
+					sm.Remove(key)
+				}
+			}
+		}(w)
+	}
+	for i := 1; i < 5; i++ {
+		_ = sm.AddShard(strconv.Itoa(i), newShard())
+	}
+	_ = sm.RemoveShard("2")
+	wg.Wait()
+	sm.Wait()
+	if sm.Size() != 4*1_500 {
+		t.Logf("size: %d", sm.Size())
+		t.FailNow()
+	}
+	for w := 0; w < 4; w++ {
+		for i := 0; i < 2_000; i++ {
+			v, ok := sm.Get(strconv.Itoa(w*10_000 + i))
+			if ok != (i%4 != 0) || ok && v != i {
+				t.Logf("key: %d, value: %d, ok: %v", w*10_000+i, v, ok)
+				t.FailNow()
+			}
+		}
+	}
+}
+
+func BenchmarkShardedMap_Put(b *testing.B) {
+	sm := NewStringShardedMap[int](0)
+	for i := 0; i < 4; i++ {
+		_ = sm.AddShard(strconv.Itoa(i), newShard())
+	}
+	sm.Wait()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			sm.Put(strconv.Itoa(i%10_000), i)
+			i++
+		}
+	})
+}
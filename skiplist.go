@@ -0,0 +1,286 @@
+package hashmap
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+)
+
+const (
+	skipListMaxLevel = 32
+	skipListP        = 4
+)
+
+// CompareFunc returns a negative number, zero or a positive number as k1 is less than, equal to or greater than k2.
+type CompareFunc[k any] func(k1, k2 k) int
+
+// Ordered is the constraint of key types which can be compared by the < operator.
+type Ordered interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64 | ~string
+}
+
+// ConcurrentSkipListMap thread-safe map sorted by keys, backed by a skip list.
+type ConcurrentSkipListMap[k, v any] struct {
+	sl *skipList[k, v]
+}
+
+type skipList[k, v any] struct {
+	sync.RWMutex
+	head  *slNode[k, v]
+	level int
+	size  int
+	cmp   CompareFunc[k]
+	rnd   *rand.Rand
+}
+
+type slNode[k, v any] struct {
+	key   k
+	value v
+	next  []*slNode[k, v]
+}
+
+// NewSkipListMap returns ConcurrentSkipListMap ordered by the < operator.
+func NewSkipListMap[k Ordered, v any]() ConcurrentSkipListMap[k, v] {
+	csm, _ := NewSkipListMapWithFunc[k, v](compareOrdered[k])
+	return csm
+}
+
+// NewSkipListMapWithFunc returns ConcurrentSkipListMap ordered by the given compare func.
+func NewSkipListMapWithFunc[k, v any](cmp CompareFunc[k]) (csm ConcurrentSkipListMap[k, v], err error) {
+	if cmp == nil {
+		err = errors.New("compare func cannot be nil")
+		return
+	}
+	csm.sl = &skipList[k, v]{
+		head:  &slNode[k, v]{next: make([]*slNode[k, v], skipListMaxLevel)},
+		level: 1,
+		cmp:   cmp,
+		rnd:   rand.New(rand.NewSource(rand.Int63())),
+	}
+	return
+}
+
+func compareOrdered[k Ordered](k1, k2 k) int {
+	if k1 < k2 {
+		return -1
+	} else if k1 > k2 {
+		return 1
+	}
+	return 0
+}
+
+// Put maps the given key to the value, and saves the entry.
+// In case of there is already an entry mapped by the given key, it updates the value of the entry.
+func (m *ConcurrentSkipListMap[k, v]) Put(key k, val v) {
+	sl := m.sl
+	sl.Lock()
+	defer sl.Unlock()
+	var update [skipListMaxLevel]*slNode[k, v]
+	x := sl.head
+	for i := sl.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && sl.cmp(x.next[i].key, key) < 0 {
+			x = x.next[i]
+		}
+		update[i] = x
+	}
+	if n := x.next[0]; n != nil && sl.cmp(n.key, key) == 0 {
+		n.value = val
+		return
+	}
+	level := sl.randomLevel()
+	if level > sl.level {
+		for i := sl.level; i < level; i++ {
+			update[i] = sl.head
+		}
+		sl.level = level
+	}
+	n := &slNode[k, v]{key: key, value: val, next: make([]*slNode[k, v], level)}
+	for i := 0; i < level; i++ {
+		n.next[i] = update[i].next[i]
+		update[i].next[i] = n
+	}
+	sl.size++
+}
+
+// Get returns value of the entry mapped by given key.
+func (m *ConcurrentSkipListMap[k, v]) Get(key k) (v, bool) {
+	sl := m.sl
+	sl.RLock()
+	defer sl.RUnlock()
+	if n := sl.ceiling(key); n != nil && sl.cmp(n.key, key) == 0 {
+		return n.value, true
+	}
+	return *new(v), false
+}
+
+// Contains returns if there is an entry mapped by the given key.
+func (m *ConcurrentSkipListMap[k, v]) Contains(key k) bool {
+	_, ok := m.Get(key)
+	return ok
+}
+
+// Remove removes the entry mapped by the given key and returns value of removed entry and true.
+func (m *ConcurrentSkipListMap[k, v]) Remove(key k) (v, bool) {
+	sl := m.sl
+	sl.Lock()
+	defer sl.Unlock()
+	var update [skipListMaxLevel]*slNode[k, v]
+	x := sl.head
+	for i := sl.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && sl.cmp(x.next[i].key, key) < 0 {
+			x = x.next[i]
+		}
+		update[i] = x
+	}
+	n := x.next[0]
+	if n == nil || sl.cmp(n.key, key) != 0 {
+		return *new(v), false
+	}
+	sl.unlink(n, update[:])
+	return n.value, true
+}
+
+// Size returns the count of entries in the map.
+func (m *ConcurrentSkipListMap[k, v]) Size() int {
+	m.sl.RLock()
+	defer m.sl.RUnlock()
+	return m.sl.size
+}
+
+// Floor returns the entry with the greatest key less than or equal to the given key.
+func (m *ConcurrentSkipListMap[k, v]) Floor(key k) (k, v, bool) {
+	sl := m.sl
+	sl.RLock()
+	defer sl.RUnlock()
+	x := sl.head
+	for i := sl.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && sl.cmp(x.next[i].key, key) <= 0 {
+			x = x.next[i]
+		}
+	}
+	if x == sl.head {
+		return *new(k), *new(v), false
+	}
+	return x.key, x.value, true
+}
+
+// Ceiling returns the entry with the least key greater than or equal to the given key.
+func (m *ConcurrentSkipListMap[k, v]) Ceiling(key k) (k, v, bool) {
+	sl := m.sl
+	sl.RLock()
+	defer sl.RUnlock()
+	n := sl.ceiling(key)
+	if n == nil {
+		return *new(k), *new(v), false
+	}
+	return n.key, n.value, true
+}
+
+// First returns the entry with the least key.
+func (m *ConcurrentSkipListMap[k, v]) First() (k, v, bool) {
+	sl := m.sl
+	sl.RLock()
+	defer sl.RUnlock()
+	n := sl.head.next[0]
+	if n == nil {
+		return *new(k), *new(v), false
+	}
+	return n.key, n.value, true
+}
+
+// Last returns the entry with the greatest key.
+func (m *ConcurrentSkipListMap[k, v]) Last() (k, v, bool) {
+	sl := m.sl
+	sl.RLock()
+	defer sl.RUnlock()
+	x := sl.head
+	for i := sl.level - 1; i >= 0; i-- {
+		for x.next[i] != nil {
+			x = x.next[i]
+		}
+	}
+	if x == sl.head {
+		return *new(k), *new(v), false
+	}
+	return x.key, x.value, true
+}
+
+// PollFirst removes the entry with the least key and returns it.
+func (m *ConcurrentSkipListMap[k, v]) PollFirst() (k, v, bool) {
+	sl := m.sl
+	sl.Lock()
+	defer sl.Unlock()
+	n := sl.head.next[0]
+	if n == nil {
+		return *new(k), *new(v), false
+	}
+	var update [skipListMaxLevel]*slNode[k, v]
+	for i := range n.next {
+		update[i] = sl.head
+	}
+	sl.unlink(n, update[:])
+	return n.key, n.value, true
+}
+
+// RangeFrom calls fn in key order for each entry whose key is greater than or equal to lo and less than hi,
+// until fn returns false. Entries are copied under the read lock before fn is called, so fn may modify the map.
+func (m *ConcurrentSkipListMap[k, v]) RangeFrom(lo, hi k, fn func(key k, val v) bool) {
+	sl := m.sl
+	sl.RLock()
+	var entries []Entry[k, v]
+	for n := sl.ceiling(lo); n != nil && sl.cmp(n.key, hi) < 0; n = n.next[0] {
+		entries = append(entries, Entry[k, v]{Key: n.key, Value: n.value})
+	}
+	sl.RUnlock()
+	for _, e := range entries {
+		if !fn(e.Key, e.Value) {
+			return
+		}
+	}
+}
+
+// Range calls fn in key order for each entry until fn returns false.
+func (m *ConcurrentSkipListMap[k, v]) Range(fn func(key k, val v) bool) {
+	sl := m.sl
+	sl.RLock()
+	entries := make([]Entry[k, v], 0, sl.size)
+	for n := sl.head.next[0]; n != nil; n = n.next[0] {
+		entries = append(entries, Entry[k, v]{Key: n.key, Value: n.value})
+	}
+	sl.RUnlock()
+	for _, e := range entries {
+		if !fn(e.Key, e.Value) {
+			return
+		}
+	}
+}
+
+func (sl *skipList[k, v]) ceiling(key k) *slNode[k, v] {
+	x := sl.head
+	for i := sl.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && sl.cmp(x.next[i].key, key) < 0 {
+			x = x.next[i]
+		}
+	}
+	return x.next[0]
+}
+
+func (sl *skipList[k, v]) unlink(n *slNode[k, v], update []*slNode[k, v]) {
+	for i := range n.next {
+		update[i].next[i] = n.next[i]
+	}
+	for sl.level > 1 && sl.head.next[sl.level-1] == nil {
+		sl.level--
+	}
+	sl.size--
+}
+
+func (sl *skipList[k, v]) randomLevel() int {
+	level := 1
+	for level < skipListMaxLevel && sl.rnd.Intn(skipListP) == 0 {
+		level++
+	}
+	return level
+}
@@ -0,0 +1,142 @@
+package hashmap
+
+import (
+	"math/rand"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentSkipListMap(t *testing.T) {
+	m := NewSkipListMap[int, int]()
+	for _, i := range rand.Perm(1_000) {
+		m.Put(i*2, i)
+	}
+	m.Put(0, -1)
+	if m.Size() != 1_000 {
+		t.Logf("size: %d", m.Size())
+		t.FailNow()
+	}
+	if v, ok := m.Get(0); !ok || v != -1 {
+		t.Logf("value: %d, ok: %v", v, ok)
+		t.FailNow()
+	}
+	if m.Contains(1) {
+		t.FailNow()
+	}
+	if k, _, ok := m.Floor(101); !ok || k != 100 {
+		t.Logf("key: %d, ok: %v", k, ok)
+		t.FailNow()
+	}
+	if k, _, ok := m.Ceiling(101); !ok || k != 102 {
+		t.Logf("key: %d, ok: %v", k, ok)
+		t.FailNow()
+	}
+	if _, _, ok := m.Floor(-1); ok {
+		t.FailNow()
+	}
+	if _, _, ok := m.Ceiling(1_999); ok {
+		t.FailNow()
+	}
+	if k, _, ok := m.First(); !ok || k != 0 {
+		t.Logf("key: %d, ok: %v", k, ok)
+		t.FailNow()
+	}
+	if k, _, ok := m.Last(); !ok || k != 1_998 {
+		t.Logf("key: %d, ok: %v", k, ok)
+		t.FailNow()
+	}
+	var keys []int
+	m.RangeFrom(10, 20, func(key, val int) bool {
+		keys = append(keys, key)
+		return true
+	})
+	if len(keys) != 5 || keys[0] != 10 || keys[4] != 18 {
+		t.Logf("keys: %v", keys)
+		t.FailNow()
+	}
+	for i := 0; i < 500; i++ {
+		if k, v, ok := m.PollFirst(); !ok || k != i*2 || (i > 0 && v != i) {
+			t.Logf("key: %d, value: %d, ok: %v", k, v, ok)
+			t.FailNow()
+		}
+	}
+	if v, ok := m.Remove(1_998); !ok || v != 999 {
+		t.Logf("value: %d, ok: %v", v, ok)
+		t.FailNow()
+	}
+	if _, ok := m.Remove(1_998); ok {
+		t.FailNow()
+	}
+	prev := -1
+	count := 0
+	m.Range(func(key, val int) bool {
+		if key <= prev {
+			t.Logf("key: %d, prev: %d", key, prev)
+			t.FailNow()
+		}
+		prev = key
+		count++
+		return true
+	})
+	if count != 499 || m.Size() != 499 {
+		t.Logf("count: %d, size: %d", count, m.Size())
+		t.FailNow()
+	}
+}
+
+func TestConcurrentSkipListMap_ConcurrentlyPut_Remove(t *testing.T) {
+	m := NewSkipListMap[int, int]()
+	var wg sync.WaitGroup
+	putRemove := func(from, to int) {
+		defer wg.Done()
+		for i := from; i < to; i++ {
+			m.Put(i, i)
+			if i%2 == 0 {
+				m.Remove(i)
+			}
+		}
+	}
+	wg.Add(3)
+	go putRemove(0, 10_000)
+	go putRemove(5_000, 15_000)
+	go putRemove(10_000, 20_000)
+	wg.Wait()
+	if m.Size() != 10_000 {
+		t.Logf("size: %d", m.Size())
+		t.FailNow()
+	}
+	for i := 0; i < 20_000; i++ {
+		if m.Contains(i) != (i%2 == 1) {
+			t.Logf("key: %d", i)
+			t.FailNow()
+		}
+	}
+}
+
+func TestNewSkipListMapWithFunc(t *testing.T) {
+	m, err := NewSkipListMapWithFunc[string, int](func(k1, k2 string) int {
+		return strings.Compare(strings.ToLower(k1), strings.ToLower(k2))
+	})
+	if err != nil {
+		t.FailNow()
+	}
+	m.Put("b", 1)
+	m.Put("A", 2)
+	m.Put("a", 3)
+	if k, v, ok := m.First(); !ok || k != "A" || v != 3 || m.Size() != 2 {
+		t.Logf("key: %s, value: %d, ok: %v", k, v, ok)
+		t.FailNow()
+	}
+	_, err = NewSkipListMapWithFunc[string, int](nil)
+	if err == nil {
+		t.FailNow()
+	}
+}
+
+func BenchmarkConcurrentSkipListMap_Put(b *testing.B) {
+	m := NewSkipListMap[int, int]()
+	for i := 0; i < b.N; i++ {
+		m.Put(rand.Int(), i)
+	}
+}
@@ -0,0 +1,116 @@
+package hashmap
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Snapshot is a read-only, immutable view of a ConcurrentHashMap as of a single instant.
+type Snapshot[k, v any] struct {
+	capacity uint32
+	table    []*bucket[k, v]
+	hf       HashFunc[k]
+	ef       EqualsFunc[k]
+}
+
+// snapshots coordinates copy-on-write buckets between Snapshot calls and writers.
+type snapshots[k, v any] struct {
+	sync.Mutex
+	version uint64
+	active  atomic.Value
+	// commit is read locked while changes spanning multiple buckets are applied,
+	// so that a snapshot cannot start in the middle of them
+	commit sync.RWMutex
+}
+
+type snapshotState[k, v any] struct {
+	version uint64
+	table   []*bucket[k, v]
+}
+
+// Snapshot returns a point-in-time copy of the map.
+// Buckets are copied one at a time, without blocking writers for the duration of the copy.
+// A writer which modifies a bucket that is not copied yet copies it first, so the snapshot
+// contains the state of every bucket as of the moment Snapshot was called.
+func (m *ConcurrentHashMap[k, v]) Snapshot() *Snapshot[k, v] {
+	return m.snapshot(nil)
+}
+
+// snapshot takes a snapshot, onStart is called once the snapshot instant is determined.
+func (m *ConcurrentHashMap[k, v]) snapshot(onStart func()) *Snapshot[k, v] {
+	m.snaps.Lock()
+	defer m.snaps.Unlock()
+	m.snaps.version++
+	s := &snapshotState[k, v]{
+		version: m.snaps.version,
+		table:   make([]*bucket[k, v], m.capacity),
+	}
+	m.snaps.commit.Lock()
+	if onStart != nil {
+		onStart()
+	}
+	m.snaps.active.Store(s)
+	m.snaps.commit.Unlock()
+	for i, b := range m.table {
+		b.RLock()
+		if b.snap != s.version {
+			s.table[i] = b.clone()
+			b.snap = s.version
+		}
+		b.RUnlock()
+	}
+	m.snaps.active.Store((*snapshotState[k, v])(nil))
+	return &Snapshot[k, v]{
+		capacity: m.capacity,
+		table:    s.table,
+		hf:       m.hf,
+		ef:       m.ef,
+	}
+}
+
+// preserve copies the bucket into the active snapshot before it gets modified,
+// the caller must hold the bucket lock.
+func (s *snapshots[k, v]) preserve(i uint32, b *bucket[k, v]) {
+	as, _ := s.active.Load().(*snapshotState[k, v])
+	if as == nil || b.snap == as.version {
+		return
+	}
+	as.table[i] = b.clone()
+	b.snap = as.version
+}
+
+// Get returns value of the entry mapped by given key.
+func (s *Snapshot[k, v]) Get(key k) (v, bool) {
+	h := s.hf(key)
+	n := s.table[h%s.capacity].get(h, key, s.ef)
+	if n == nil {
+		return *new(v), false
+	}
+	return n.value, true
+}
+
+// Contains returns if there is an entry mapped by the given key.
+func (s *Snapshot[k, v]) Contains(key k) bool {
+	h := s.hf(key)
+	return s.table[h%s.capacity].get(h, key, s.ef) != nil
+}
+
+// Size returns the count of entries in the snapshot.
+func (s *Snapshot[k, v]) Size() int {
+	var size int64 = 0
+	for _, b := range s.table {
+		size += b.size
+	}
+	return int(size)
+}
+
+// Range calls fn for each entry in the snapshot until fn returns false.
+func (s *Snapshot[k, v]) Range(fn func(key k, val v) bool) {
+	for _, b := range s.table {
+		if !b.each(func(n *node[k, v]) bool {
+			return fn(n.key, n.value)
+		}) {
+			return
+		}
+	}
+}
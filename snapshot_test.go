@@ -0,0 +1,88 @@
+package hashmap
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentHashMap_Snapshot(t *testing.T) {
+	m := NewString[int]()
+	for i := 0; i < 1_000; i++ {
+		m.Put(strconv.Itoa(i), i)
+	}
+	s := m.Snapshot()
+	for i := 0; i < 500; i++ {
+		m.Remove(strconv.Itoa(i))
+	}
+	for i := 1_000; i < 1_500; i++ {
+		m.Put(strconv.Itoa(i), i)
+	}
+	if size := s.Size(); size != 1_000 {
+		t.Logf("size: %d", size)
+		t.FailNow()
+	}
+	for i := 0; i < 1_000; i++ {
+		k := strconv.Itoa(i)
+		if v, ok := s.Get(k); !ok || v != i {
+			t.Logf("key: %s, value: %v, ok: %v", k, v, ok)
+			t.FailNow()
+		}
+	}
+	if s.Contains("1000") {
+		t.FailNow()
+	}
+	count := 0
+	s.Range(func(key string, val int) bool {
+		count++
+		return true
+	})
+	if count != 1_000 {
+		t.Logf("count: %d", count)
+		t.FailNow()
+	}
+}
+
+func TestConcurrentHashMap_ConcurrentlyTx_Snapshot(t *testing.T) {
+	m := NewString[int]()
+	for i := 0; i < 100; i++ {
+		m.Put(strconv.Itoa(i), 100)
+	}
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	transfer := func(from, to int) {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			src, dst := strconv.Itoa((from+i)%100), strconv.Itoa((to+i*7)%100)
+			_ = m.Tx([]string{src, dst}, func(tx TxView[string, int]) error {
+				sv, _ := tx.Get(src)
+				dv, _ := tx.Get(dst)
+				tx.Put(src, sv-1)
+				tx.Put(dst, dv+1)
+				return nil
+			})
+		}
+	}
+	wg.Add(2)
+	go transfer(0, 1)
+	go transfer(50, 3)
+	for i := 0; i < 100; i++ {
+		sum := 0
+		m.Snapshot().Range(func(key string, val int) bool {
+			sum += val
+			return true
+		})
+		if sum != 100*100 {
+			close(done)
+			t.Logf("sum: %d", sum)
+			t.FailNow()
+		}
+	}
+	close(done)
+	wg.Wait()
+}
@@ -0,0 +1,34 @@
+package hashmap
+
+// Stats is a summary of the entries and the bucket distribution of a map.
+type Stats struct {
+	Size          int `json:"size"`
+	Capacity      int `json:"capacity"`
+	EmptyBuckets  int `json:"empty_buckets"`
+	TreeBuckets   int `json:"tree_buckets"`
+	MaxBucketSize int `json:"max_bucket_size"`
+	// HotBuckets are the most accessed buckets, if hot key tracking is enabled by EnableHotKeys.
+	HotBuckets []HotBucket `json:"hot_buckets,omitempty"`
+}
+
+// Stats returns the stats of the map, reading each bucket under its read lock.
+func (m *ConcurrentHashMap[k, v]) Stats() Stats {
+	s := Stats{Capacity: int(m.capacity), HotBuckets: m.HotBuckets()}
+	for _, b := range m.table {
+		b.RLock()
+		size := int(b.size)
+		tree := b.tree
+		b.RUnlock()
+		s.Size += size
+		if size == 0 {
+			s.EmptyBuckets++
+		}
+		if tree {
+			s.TreeBuckets++
+		}
+		if size > s.MaxBucketSize {
+			s.MaxBucketSize = size
+		}
+	}
+	return s
+}
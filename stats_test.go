@@ -0,0 +1,18 @@
+package hashmap
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestConcurrentHashMap_Stats(t *testing.T) {
+	m := NewString[int]()
+	for i := 0; i < 1_000; i++ {
+		m.Put(strconv.Itoa(i), i)
+	}
+	s := m.Stats()
+	if s.Size != 1_000 || s.Capacity != defaultCapacity || s.EmptyBuckets != 0 || s.TreeBuckets != defaultCapacity || s.MaxBucketSize < 1_000/defaultCapacity {
+		t.Logf("stats: %+v", s)
+		t.FailNow()
+	}
+}
@@ -0,0 +1,169 @@
+package hashmap
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrKeyNotInTx is returned by Tx when the callback accesses a key which is not part of the transaction.
+var ErrKeyNotInTx = errors.New("key is not part of the transaction")
+
+// TxView provides access to the keys of a transaction while their buckets are locked.
+// Changes made through the view are applied to the map only if the transaction callback returns nil.
+type TxView[k, v any] interface {
+	// Get returns the value mapped by the given key, including the changes made in the transaction.
+	Get(key k) (v, bool)
+	// Put maps the given key to the value.
+	Put(key k, val v)
+	// Remove removes the entry mapped by the given key and returns its value.
+	Remove(key k) (v, bool)
+	// SetExpiry sets the expiration time of the entry mapped by the given key, as ConcurrentHashMap.SetExpiry.
+	// Putting the key later in the transaction clears it.
+	SetExpiry(key k, at time.Time) bool
+	// Expiry returns the expiration time of the entry mapped by the given key, including the changes made in the transaction.
+	Expiry(key k) (time.Time, bool)
+}
+
+type txEntry[k, v any] struct {
+	hash     uint32
+	key      k
+	value    v
+	removed  bool
+	expiry   bool
+	expireAt int64
+}
+
+type txView[k, v any] struct {
+	m      *ConcurrentHashMap[k, v]
+	keys   []txEntry[k, v]
+	writes []txEntry[k, v]
+	err    error
+}
+
+// Tx locks the buckets of the given keys and runs fn with a view of them.
+// The bucket locks are acquired in ascending bucket order, so concurrent transactions cannot deadlock.
+// All changes made through the view are applied if fn returns nil, none of them otherwise.
+// Accessing a key which is not in keys makes Tx discard the changes and return ErrKeyNotInTx.
+// fn must not call other methods of the map, since the buckets are already locked.
+func (m *ConcurrentHashMap[k, v]) Tx(keys []k, fn func(tx TxView[k, v]) error) error {
+	tx := &txView[k, v]{
+		m:    m,
+		keys: make([]txEntry[k, v], len(keys)),
+	}
+	hashes := make([]uint32, len(keys))
+	for i, key := range keys {
+		hashes[i] = m.hf(key)
+		tx.keys[i] = txEntry[k, v]{hash: hashes[i], key: key}
+	}
+	indices, _ := m.groupByBucket(hashes)
+	for _, i := range indices {
+		m.table[i].Lock()
+	}
+	defer func() {
+		for j := len(indices) - 1; j >= 0; j-- {
+			m.table[indices[j]].Unlock()
+		}
+	}()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if tx.err != nil {
+		return tx.err
+	}
+	m.snaps.commit.RLock()
+	for _, e := range tx.writes {
+		switch {
+		case e.removed:
+			m.remove(e.hash, e.key)
+		case e.expiry:
+			m.setExpiry(e.hash%m.capacity, e.hash, e.key, e.expireAt)
+		default:
+			m.put(e.hash, e.key, e.value)
+		}
+	}
+	m.snaps.commit.RUnlock()
+	return nil
+}
+
+func (tx *txView[k, v]) Get(key k) (v, bool) {
+	h, ok := tx.check(key)
+	if !ok {
+		return *new(v), false
+	}
+	for i := len(tx.writes) - 1; i >= 0; i-- {
+		if e := tx.writes[i]; !e.expiry && e.hash == h && tx.m.ef(e.key, key) {
+			if e.removed {
+				return *new(v), false
+			}
+			return e.value, true
+		}
+	}
+	n := tx.m.table[h%tx.m.capacity].get(h, key, tx.m.ef)
+	if n == nil {
+		return *new(v), false
+	}
+	return n.value, true
+}
+
+func (tx *txView[k, v]) Put(key k, val v) {
+	h, ok := tx.check(key)
+	if !ok {
+		return
+	}
+	tx.writes = append(tx.writes, txEntry[k, v]{hash: h, key: key, value: val})
+}
+
+func (tx *txView[k, v]) Remove(key k) (v, bool) {
+	val, ok := tx.Get(key)
+	if !ok {
+		return val, false
+	}
+	h := tx.m.hf(key)
+	tx.writes = append(tx.writes, txEntry[k, v]{hash: h, key: key, removed: true})
+	return val, true
+}
+
+func (tx *txView[k, v]) SetExpiry(key k, at time.Time) bool {
+	if _, ok := tx.Get(key); !ok {
+		return false
+	}
+	h := tx.m.hf(key)
+	tx.writes = append(tx.writes, txEntry[k, v]{hash: h, key: key, expiry: true, expireAt: unixNano(at)})
+	return true
+}
+
+func (tx *txView[k, v]) Expiry(key k) (time.Time, bool) {
+	h, ok := tx.check(key)
+	if !ok {
+		return time.Time{}, false
+	}
+	at := int64(0)
+	found := false
+	for i := len(tx.writes) - 1; i >= 0 && !found; i-- {
+		if e := tx.writes[i]; e.hash == h && tx.m.ef(e.key, key) {
+			// a put or a remove clears the expiration time
+			at, found = e.expireAt, true
+		}
+	}
+	if !found {
+		if n := tx.m.table[h%tx.m.capacity].get(h, key, tx.m.ef); n != nil {
+			at = n.expireAt
+		}
+	}
+	if at == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(0, at), true
+}
+
+// check returns the hash of the key and whether the key is part of the transaction.
+func (tx *txView[k, v]) check(key k) (uint32, bool) {
+	h := tx.m.hf(key)
+	for _, e := range tx.keys {
+		if e.hash == h && tx.m.ef(e.key, key) {
+			return h, true
+		}
+	}
+	tx.err = ErrKeyNotInTx
+	return h, false
+}
@@ -0,0 +1,114 @@
+package hashmap
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentHashMap_Tx(t *testing.T) {
+	m := NewString[int]()
+	m.Put("alice", 100)
+	m.Put("bob", 50)
+	err := m.Tx([]string{"alice", "bob"}, func(tx TxView[string, int]) error {
+		a, _ := tx.Get("alice")
+		b, _ := tx.Get("bob")
+		tx.Put("alice", a-30)
+		tx.Put("bob", b+30)
+		return nil
+	})
+	if err != nil {
+		t.Logf("err: %v", err)
+		t.FailNow()
+	}
+	if v, _ := m.Get("alice"); v != 70 {
+		t.Logf("alice: %d", v)
+		t.FailNow()
+	}
+	if v, _ := m.Get("bob"); v != 80 {
+		t.Logf("bob: %d", v)
+		t.FailNow()
+	}
+}
+
+func TestConcurrentHashMap_Tx_Rollback(t *testing.T) {
+	m := NewString[int]()
+	m.Put("alice", 100)
+	m.Put("bob", 50)
+	txErr := errors.New("insufficient balance")
+	err := m.Tx([]string{"alice", "bob", "carol"}, func(tx TxView[string, int]) error {
+		tx.Remove("bob")
+		tx.Put("carol", 10)
+		if _, ok := tx.Get("bob"); ok {
+			t.Log("removed key is visible in tx")
+			t.FailNow()
+		}
+		if v, ok := tx.Get("carol"); !ok || v != 10 {
+			t.Logf("carol: %d, ok: %v", v, ok)
+			t.FailNow()
+		}
+		return txErr
+	})
+	if err != txErr {
+		t.Logf("err: %v", err)
+		t.FailNow()
+	}
+	if v, ok := m.Get("bob"); !ok || v != 50 {
+		t.Logf("bob: %d, ok: %v", v, ok)
+		t.FailNow()
+	}
+	if m.Contains("carol") {
+		t.FailNow()
+	}
+}
+
+func TestConcurrentHashMap_Tx_KeyNotInTx(t *testing.T) {
+	m := NewString[int]()
+	err := m.Tx([]string{"alice"}, func(tx TxView[string, int]) error {
+		tx.Put("alice", 1)
+		tx.Put("bob", 1)
+		return nil
+	})
+	if err != ErrKeyNotInTx {
+		t.Logf("err: %v", err)
+		t.FailNow()
+	}
+	if m.Size() != 0 {
+		t.FailNow()
+	}
+}
+
+func TestConcurrentHashMap_ConcurrentlyTx(t *testing.T) {
+	m := NewString[int]()
+	for i := 0; i < 100; i++ {
+		m.Put(strconv.Itoa(i), 100)
+	}
+	var wg sync.WaitGroup
+	transfer := func(from, to int) {
+		defer wg.Done()
+		for i := 0; i < 1_000; i++ {
+			src, dst := strconv.Itoa((from+i)%100), strconv.Itoa((to+i)%100)
+			_ = m.Tx([]string{src, dst}, func(tx TxView[string, int]) error {
+				sv, _ := tx.Get(src)
+				dv, _ := tx.Get(dst)
+				tx.Put(src, sv-1)
+				tx.Put(dst, dv+1)
+				return nil
+			})
+		}
+	}
+	wg.Add(2)
+	go transfer(0, 1)
+	go transfer(1, 0)
+	wg.Wait()
+	sum := 0
+	for i := 0; i < 100; i++ {
+		v, _ := m.Get(strconv.Itoa(i))
+		sum += v
+	}
+	if sum != 100*100 {
+		t.Logf("sum: %d", sum)
+		t.FailNow()
+	}
+}